@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	unpack "github.com/novopashinwm/OtusGoLang/tree/master/hw02_unpack_string"
+)
+
+var errUnknownCommand = errors.New("unknown command")
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s pack|unpack|validate [flags] [file ...]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "Run %s <command> -h to see flags of the command.\n", os.Args[0])
+	}
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(usageCode)
+	}
+
+	cmd, opts, files, err := parseArgs(flag.Args(), os.Stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(successCode)
+	case errors.Is(err, errUnknownCommand):
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(usageCode)
+	case err != nil:
+		os.Exit(usageCode)
+	}
+	os.Exit(Run(cmd, opts, files, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// parseArgs parses the command name followed by its flags and files. Errors of flags
+// are reported to output with the usage of the command, flags of unpack.Options
+// are accepted only by commands using them.
+func parseArgs(args []string, output io.Writer) (Command, unpack.Options, []string, error) {
+	opts := unpack.DefaultOptions
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return cmd, opts, nil, fmt.Errorf("%w %q", errUnknownCommand, args[0])
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(output)
+	if cmd.Options {
+		flags.BoolVar(&opts.MultiDigitCount, "multi", false, "allow repeat counts of several digits")
+		flags.BoolVar(&opts.Graphemes, "graphemes", false, "repeat whole grapheme clusters")
+		flags.IntVar(&opts.MaxLength, "max", 0, "max length of an unpacked line in bytes, 0 means no limit")
+	}
+	flags.Usage = func() {
+		fmt.Fprintf(output, "Usage: %s %s [flags] [file ...]\n", os.Args[0], args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return cmd, opts, nil, err
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{stdinName}
+	}
+	return cmd, opts, files, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	unpack "github.com/novopashinwm/OtusGoLang/tree/master/hw02_unpack_string"
+)
+
+// Коды возврата совпадают с hw03_frequency_analysis/cmd/top.
+const (
+	successCode     = 0
+	ioErrorCode     = 1
+	usageCode       = 2
+	invalidDataCode = 3
+)
+
+const stdinName = "-"
+
+// Command converts a single line, the result is printed only if Print is set.
+type Command struct {
+	Convert func(line string, opts unpack.Options) (string, error)
+	Print   bool
+	// Options tells that Convert uses unpack.Options, so the command accepts their flags.
+	Options bool
+}
+
+var commands = map[string]Command{
+	"pack": {
+		Convert: func(line string, _ unpack.Options) (string, error) { return unpack.Pack(line) },
+		Print:   true,
+	},
+	"unpack": {
+		Convert: unpack.UnpackWithOptions,
+		Print:   true,
+		Options: true,
+	},
+	"validate": {
+		Convert: func(line string, opts unpack.Options) (string, error) {
+			// Результат не нужен, поэтому проверяем без выделения памяти под него.
+			_, err := unpack.UnpackStream(io.Discard, strings.NewReader(line), opts)
+			return "", err
+		},
+		Options: true,
+	},
+}
+
+// Run applies cmd to every line of files ("-" stands for stdin) and returns the exit code:
+// invalidDataCode if some lines couldn't be converted, ioErrorCode if reading or writing failed.
+func Run(cmd Command, opts unpack.Options, files []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	out := bufio.NewWriter(stdout)
+	returnCode := successCode
+	for _, name := range files {
+		valid, err := runFile(cmd, opts, name, stdin, out, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			out.Flush()
+			return ioErrorCode
+		}
+		if !valid {
+			returnCode = invalidDataCode
+		}
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return ioErrorCode
+	}
+	return returnCode
+}
+
+func runFile(cmd Command, opts unpack.Options, name string, stdin io.Reader, out *bufio.Writer,
+	stderr io.Writer) (bool, error) {
+	in := stdin
+	if name != stdinName {
+		f, err := os.Open(name)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	valid := true
+	reader := bufio.NewReader(in)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) && line == "" {
+			return valid, nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("%s: %w", name, err)
+		}
+		// CR снимаем только в паре с LF, одиночный CR в конце файла - часть строки.
+		if strings.HasSuffix(line, "\n") {
+			line = strings.TrimSuffix(line[:len(line)-1], "\r")
+		}
+
+		result, convErr := cmd.Convert(line, opts)
+		if convErr != nil {
+			valid = false
+			printDiagnostic(stderr, name, lineNum, convErr)
+			continue
+		}
+		if cmd.Print {
+			if _, err := fmt.Fprintln(out, result); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+func printDiagnostic(stderr io.Writer, name string, lineNum int, err error) {
+	var syntaxErr *unpack.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		fmt.Fprintf(stderr, "%s:%d:%d: %s: %s %q\n",
+			name, lineNum, syntaxErr.Pos+1, unpack.ErrInvalidString, syntaxErr.Reason, syntaxErr.Rune)
+		return
+	}
+	fmt.Fprintf(stderr, "%s:%d: %s\n", name, lineNum, err)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	unpack "github.com/novopashinwm/OtusGoLang/tree/master/hw02_unpack_string"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	run := func(cmdName string, input string, files ...string) (int, string, string) {
+		if len(files) == 0 {
+			files = []string{stdinName}
+		}
+		var stdout, stderr bytes.Buffer
+		code := Run(commands[cmdName], unpack.DefaultOptions, files, strings.NewReader(input), &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	t.Run("unpack", func(t *testing.T) {
+		code, stdout, stderr := run("unpack", "a4bc2d5e\nqwe\\45\n\nab")
+		require.Equal(t, successCode, code)
+		require.Equal(t, "aaaabccddddde\nqwe44444\n\nab\n", stdout)
+		require.Empty(t, stderr)
+	})
+
+	t.Run("pack", func(t *testing.T) {
+		code, stdout, _ := run("pack", "aaaaaaaaaaaa\nqwe45\n")
+		require.Equal(t, successCode, code)
+		require.Equal(t, "a9a3\nqwe\\4\\5\n", stdout)
+	})
+
+	t.Run("crlf", func(t *testing.T) {
+		code, stdout, _ := run("unpack", "a4\r\nb2\r\n")
+		require.Equal(t, successCode, code)
+		require.Equal(t, "aaaa\nbb\n", stdout)
+
+		code, stdout, _ = run("pack", "aaa\r\nbb\r")
+		require.Equal(t, successCode, code)
+		require.Equal(t, "a3\nb2\r\n", stdout, "a lone CR must be kept")
+	})
+
+	t.Run("invalid lines", func(t *testing.T) {
+		code, stdout, stderr := run("unpack", "3abc\nab2\nqw\\ne\n")
+		require.Equal(t, invalidDataCode, code)
+		require.Equal(t, "abb\n", stdout)
+		require.Equal(t, "-:1:1: invalid string: leading digit '3'\n"+
+			"-:3:4: invalid string: escaped letter 'n'\n", stderr)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		code, stdout, stderr := run("validate", "a4\naaa10b\n")
+		require.Equal(t, invalidDataCode, code)
+		require.Empty(t, stdout)
+		require.Equal(t, "-:2:5: invalid string: consecutive digits '0'\n", stderr)
+
+		code, _, stderr = run("validate", "a4\n")
+		require.Equal(t, successCode, code)
+		require.Empty(t, stderr)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		dir := t.TempDir()
+		name := filepath.Join(dir, "packed.txt")
+		require.NoError(t, os.WriteFile(name, []byte("x3\n"), 0o600))
+
+		code, stdout, _ := run("unpack", "y2\n", name, stdinName)
+		require.Equal(t, successCode, code)
+		require.Equal(t, "xxx\nyy\n", stdout)
+	})
+
+	t.Run("io error", func(t *testing.T) {
+		code, _, stderr := run("unpack", "", filepath.Join(t.TempDir(), "missing.txt"))
+		require.Equal(t, ioErrorCode, code)
+		require.Contains(t, stderr, "missing.txt")
+	})
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Run("options after command", func(t *testing.T) {
+		var output bytes.Buffer
+		cmd, opts, files, err := parseArgs([]string{"unpack", "-multi", "-max", "10", "a.txt"}, &output)
+		require.NoError(t, err)
+		require.True(t, cmd.Print)
+		require.Equal(t, unpack.Options{MultiDigitCount: true, EscapeRune: '\\', MaxLength: 10}, opts)
+		require.Equal(t, []string{"a.txt"}, files)
+		require.Empty(t, output.String())
+	})
+
+	t.Run("stdin by default", func(t *testing.T) {
+		_, opts, files, err := parseArgs([]string{"validate"}, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.Equal(t, unpack.DefaultOptions, opts)
+		require.Equal(t, []string{stdinName}, files)
+	})
+
+	t.Run("options of pack", func(t *testing.T) {
+		var output bytes.Buffer
+		_, _, _, err := parseArgs([]string{"pack", "-graphemes"}, &output)
+		require.Error(t, err)
+		require.Contains(t, output.String(), "flag provided but not defined: -graphemes")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, _, _, err := parseArgs([]string{"zip"}, &bytes.Buffer{})
+		require.Truef(t, errors.Is(err, errUnknownCommand), "actual error %q", err)
+	})
+}
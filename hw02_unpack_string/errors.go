@@ -0,0 +1,72 @@
+package hw02unpackstring
+
+import "fmt"
+
+// Reason tells why the packed string is invalid.
+type Reason int
+
+const (
+	// ReasonLeadingDigit - the string starts with a repeat count.
+	ReasonLeadingDigit Reason = iota + 1
+	// ReasonConsecutiveDigits - a repeat count is followed by one more digit.
+	ReasonConsecutiveDigits
+	// ReasonDanglingEscape - the string ends with an unpaired escape rune.
+	ReasonDanglingEscape
+	// ReasonEscapedLetter - something other than a digit or the escape rune is escaped.
+	ReasonEscapedLetter
+	// ReasonInvalidCount - the repeat count isn't an ASCII number or doesn't fit into int.
+	ReasonInvalidCount
+	// ReasonLongCluster - a grapheme cluster has more than maxClusterExtenders runes after the first one.
+	ReasonLongCluster
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonLeadingDigit:
+		return "leading digit"
+	case ReasonConsecutiveDigits:
+		return "consecutive digits"
+	case ReasonDanglingEscape:
+		return "dangling escape"
+	case ReasonEscapedLetter:
+		return "escaped letter"
+	case ReasonInvalidCount:
+		return "invalid repeat count"
+	case ReasonLongCluster:
+		return "too long grapheme cluster"
+	}
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// SyntaxError describes where and why the packed string is invalid.
+// It matches ErrInvalidString with errors.Is.
+type SyntaxError struct {
+	Reason Reason
+	// Rune is the offending rune.
+	Rune rune
+	// Pos is the offset of Rune in runes, Offset is the same in bytes.
+	Pos    int
+	Offset int64
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s %q at position %d", ErrInvalidString, e.Reason, e.Rune, e.Pos)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrInvalidString
+}
+
+func (e SyntaxError) withReason(reason Reason) *SyntaxError {
+	e.Reason = reason
+	return &e
+}
+
+func newSyntaxError(chars []rune, pos int, reason Reason) *SyntaxError {
+	return &SyntaxError{
+		Reason: reason,
+		Rune:   chars[pos],
+		Pos:    pos,
+		Offset: int64(len(string(chars[:pos]))),
+	}
+}
@@ -0,0 +1,84 @@
+package hw02unpackstring
+
+import "unicode"
+
+const (
+	zeroWidthJoiner    = '\u200d'
+	zeroWidthNonJoiner = '\u200c'
+	// maxClusterExtenders limits runes joining the first one of a cluster like the stream-safe
+	// format of UAX #15 limits non-starters, so a cluster is never buffered without bound.
+	maxClusterExtenders = 30
+)
+
+// clusterState tracks just enough of an extended grapheme cluster to find where it ends.
+// It implements the rules of UAX #29 that matter for text with combining marks,
+// emoji sequences and flags; Hangul jamo and prepended concatenation marks
+// are split rune by rune.
+type clusterState struct {
+	prev rune
+	// pictographic is true while the cluster is a pictograph followed by Extend and ZWJ only.
+	pictographic bool
+	regional     int
+}
+
+func newClusterState(first rune) clusterState {
+	state := clusterState{prev: first, pictographic: isPictographic(first)}
+	if isRegionalIndicator(first) {
+		state.regional = 1
+	}
+	return state
+}
+
+// extends reports whether next continues the cluster and takes it into account if so.
+func (s *clusterState) extends(next rune) bool {
+	joins := false
+	switch {
+	case s.prev == '\r':
+		joins = next == '\n'
+	case isControl(s.prev) || isControl(next):
+	case isExtend(next) || next == zeroWidthJoiner:
+		joins = true
+	case unicode.Is(unicode.Mc, next):
+		joins = true
+		s.pictographic = false
+	case s.prev == zeroWidthJoiner && s.pictographic && isPictographic(next):
+		joins = true
+	case s.regional%2 == 1 && isRegionalIndicator(next):
+		joins = true
+		s.regional++
+	}
+	if joins {
+		s.prev = next
+	}
+	return joins
+}
+
+func isControl(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029'
+}
+
+func isExtend(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return true
+	case r == zeroWidthNonJoiner:
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // модификаторы цвета кожи
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // теги субрегиональных флагов
+		return true
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isPictographic approximates the Extended_Pictographic property.
+func isPictographic(r rune) bool {
+	if isRegionalIndicator(r) || (r >= 0x1f3fb && r <= 0x1f3ff) {
+		return false
+	}
+	return unicode.Is(unicode.So, r) || (r >= 0x1f000 && r <= 0x1faff)
+}
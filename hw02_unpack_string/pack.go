@@ -0,0 +1,56 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrInvalidUTF8 = errors.New("invalid utf-8 string")
+
+const maxRepeatCount = 9
+
+// Pack encodes inStr into the form understood by Unpack, so Unpack(Pack(s)) == s.
+// Digits and backslashes are escaped, runs longer than 9 are split into several groups.
+func Pack(inStr string) (string, error) {
+	if !utf8.ValidString(inStr) {
+		return "", ErrInvalidUTF8
+	}
+
+	var sb strings.Builder
+	chars := []rune(inStr)
+	num := len(chars)
+	for i := 0; i < num; {
+		j := i + 1
+		for j < num && chars[j] == chars[i] {
+			j++
+		}
+		writeRun(&sb, chars[i], j-i)
+		i = j
+	}
+	return sb.String(), nil
+}
+
+func writeRun(sb *strings.Builder, char rune, count int) {
+	for count > 0 {
+		group := count
+		if group > maxRepeatCount {
+			group = maxRepeatCount
+		}
+		writePackedRune(sb, char)
+		if group > 1 {
+			sb.WriteString(strconv.Itoa(group))
+		}
+		count -= group
+	}
+}
+
+func writePackedRune(sb *strings.Builder, char rune) {
+	const escapeCharacter = '\\'
+	if char == escapeCharacter || unicode.IsDigit(char) {
+		sb.WriteRune(escapeCharacter)
+	}
+	sb.WriteRune(char)
+}
@@ -0,0 +1,77 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "aaaabccddddde", expected: "a4bc2d5e"},
+		{input: "abccd", expected: "abc2d"},
+		{input: "", expected: ""},
+		{input: "aaaaaaaaaaaa", expected: "a9a3"},
+		{input: "aaaaaaaaa", expected: "a9"},
+		{input: "aaaaaaaaaa", expected: "a9a"},
+		{input: `qwe45`, expected: `qwe\4\5`},
+		{input: `qwe44444`, expected: `qwe\45`},
+		{input: `qwe\\\\\`, expected: `qwe\\5`},
+		{input: `qwe\3`, expected: `qwe\\\3`},
+		{input: `\\3`, expected: `\\2\3`},
+		{input: "ффыы\n\n", expected: "ф2ы2\n2"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := Pack(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPackInvalidUTF8(t *testing.T) {
+	_, err := Pack("a\xffb")
+	require.Truef(t, errors.Is(err, ErrInvalidUTF8), "actual error %q", err)
+}
+
+func TestPackRoundTrip(t *testing.T) {
+	roundTrip := func(s string) bool {
+		packed, err := Pack(s)
+		if err != nil {
+			return false
+		}
+		unpacked, err := Unpack(packed)
+		return err == nil && unpacked == s
+	}
+
+	t.Run("arbitrary unicode", func(t *testing.T) {
+		require.NoError(t, quick.Check(roundTrip, nil))
+	})
+
+	t.Run("long runs of special runes", func(t *testing.T) {
+		alphabet := []rune{'a', 'я', '\\', '0', '5', '9', '\n', '٣', '😀'}
+		config := &quick.Config{
+			MaxCount: 1000,
+			Values: func(values []reflect.Value, rnd *rand.Rand) {
+				var sb strings.Builder
+				for i := rnd.Intn(10); i > 0; i-- {
+					char := string(alphabet[rnd.Intn(len(alphabet))])
+					sb.WriteString(strings.Repeat(char, 1+rnd.Intn(25)))
+				}
+				values[0] = reflect.ValueOf(sb.String())
+			},
+		}
+		require.NoError(t, quick.Check(roundTrip, config))
+	})
+}
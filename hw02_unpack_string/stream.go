@@ -0,0 +1,190 @@
+package hw02unpackstring
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+const streamChunkSize = 4096
+
+// UnpackStream reads packed data from r and writes it unpacked to w.
+// Unlike UnpackWithOptions it keeps neither the input nor the output in memory,
+// so the memory usage doesn't depend on repeat counts.
+func UnpackStream(w io.Writer, r io.Reader, opts Options) (written int64, err error) {
+	dec := &streamDecoder{
+		in:   bufio.NewReader(r),
+		out:  bufio.NewWriterSize(w, streamChunkSize),
+		opts: opts,
+	}
+	err = dec.run()
+	if flushErr := dec.out.Flush(); err == nil {
+		err = flushErr
+	}
+	return dec.written, err
+}
+
+type streamDecoder struct {
+	in      *bufio.Reader
+	out     *bufio.Writer
+	opts    Options
+	pos     int
+	offset  int64
+	written int64
+	unit    []byte
+	chunk   []byte
+}
+
+func (dec *streamDecoder) run() error {
+	for {
+		curr, currErr, err := dec.readRune()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if unicode.IsDigit(curr) {
+			if currErr.Pos == 0 {
+				return currErr.withReason(ReasonLeadingDigit)
+			}
+			return currErr.withReason(ReasonConsecutiveDigits)
+		}
+		if dec.opts.EscapeRune != 0 && curr == dec.opts.EscapeRune {
+			escapeErr := currErr
+			curr, currErr, err = dec.readRune()
+			if errors.Is(err, io.EOF) {
+				return escapeErr.withReason(ReasonDanglingEscape)
+			}
+			if err != nil {
+				return err
+			}
+			if !unicode.IsDigit(curr) && curr != dec.opts.EscapeRune {
+				return currErr.withReason(ReasonEscapedLetter)
+			}
+		}
+
+		if err := dec.readUnit(curr); err != nil {
+			return err
+		}
+		repeatNum, err := dec.readCount()
+		if err != nil {
+			return err
+		}
+		if err := checkLength(int(dec.written), len(dec.unit), repeatNum, dec.opts.MaxLength); err != nil {
+			return err
+		}
+		if err := dec.writeRepeat(repeatNum); err != nil {
+			return err
+		}
+	}
+}
+
+// readRune returns the next rune with its location prepared for reporting.
+func (dec *streamDecoder) readRune() (rune, SyntaxError, error) {
+	char, size, err := dec.in.ReadRune()
+	if err != nil {
+		return 0, SyntaxError{}, err
+	}
+	location := SyntaxError{Rune: char, Pos: dec.pos, Offset: dec.offset}
+	dec.pos++
+	dec.offset += int64(size)
+	return char, location, nil
+}
+
+func (dec *streamDecoder) unreadRune(location SyntaxError) error {
+	dec.pos = location.Pos
+	dec.offset = location.Offset
+	return dec.in.UnreadRune()
+}
+
+// readUnit collects the repeated unit starting with first into dec.unit:
+// the rune itself or the whole grapheme cluster if Graphemes is set.
+// The cluster is limited by maxClusterExtenders, so the memory usage stays bounded.
+func (dec *streamDecoder) readUnit(first rune) error {
+	dec.unit = appendRune(dec.unit[:0], first)
+	if !dec.opts.Graphemes {
+		return nil
+	}
+	state := newClusterState(first)
+	for extenders := 0; ; extenders++ {
+		char, location, err := dec.readRune()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !state.extends(char) {
+			return dec.unreadRune(location)
+		}
+		if extenders == maxClusterExtenders {
+			return location.withReason(ReasonLongCluster)
+		}
+		dec.unit = appendRune(dec.unit, char)
+	}
+}
+
+// readCount reads the repeat count following a rune, 1 is returned if there is none.
+func (dec *streamDecoder) readCount() (int, error) {
+	var countStart SyntaxError
+	repeatNum := 1
+	for digits := 0; digits == 0 || dec.opts.MultiDigitCount; digits++ {
+		char, location, err := dec.readRune()
+		if errors.Is(err, io.EOF) {
+			return repeatNum, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsDigit(char) {
+			return repeatNum, dec.unreadRune(location)
+		}
+		if char < '0' || char > '9' {
+			return 0, location.withReason(ReasonInvalidCount)
+		}
+		if digits == 0 {
+			countStart = location
+			repeatNum = 0
+		}
+		digit := int(char - '0')
+		if repeatNum > (maxInt-digit)/10 {
+			return 0, countStart.withReason(ReasonInvalidCount)
+		}
+		repeatNum = repeatNum*10 + digit
+	}
+	return repeatNum, nil
+}
+
+func appendRune(buf []byte, char rune) []byte {
+	var encoded [utf8.UTFMax]byte
+	size := utf8.EncodeRune(encoded[:], char)
+	return append(buf, encoded[:size]...)
+}
+
+func (dec *streamDecoder) writeRepeat(repeatNum int) error {
+	size := len(dec.unit)
+	dec.chunk = dec.chunk[:0]
+	for len(dec.chunk)+size <= streamChunkSize && len(dec.chunk)/size < repeatNum {
+		dec.chunk = append(dec.chunk, dec.unit...)
+	}
+	if len(dec.chunk) == 0 && repeatNum > 0 {
+		// Кластер длиннее буфера пишем как есть.
+		dec.chunk = append(dec.chunk, dec.unit...)
+	}
+	perChunk := len(dec.chunk) / size
+	for repeatNum > 0 {
+		n := perChunk
+		if repeatNum < n {
+			n = repeatNum
+		}
+		if _, err := dec.out.Write(dec.chunk[:n*size]); err != nil {
+			return err
+		}
+		dec.written += int64(n * size)
+		repeatNum -= n
+	}
+	return nil
+}
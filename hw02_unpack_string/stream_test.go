@@ -0,0 +1,100 @@
+package hw02unpackstring
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackStream(t *testing.T) {
+	inputs := []string{
+		"a4bc2d5e", "abccd", "", "aaa0b", "d\n5abc", "ф3я",
+		`qwe\4\5`, `qwe\45`, `qwe\54`, `qwe\\5`, `qwe\\3`, `qwe\\\3`, `\\2\3`,
+	}
+
+	for _, input := range inputs {
+		input := input
+		t.Run(input, func(t *testing.T) {
+			expected, err := Unpack(input)
+			require.NoError(t, err)
+
+			var sb strings.Builder
+			written, err := UnpackStream(&sb, strings.NewReader(input), DefaultOptions)
+			require.NoError(t, err)
+			require.Equal(t, expected, sb.String())
+			require.Equal(t, int64(len(expected)), written)
+		})
+	}
+
+	t.Run("long run", func(t *testing.T) {
+		var sb strings.Builder
+		opts := Options{MultiDigitCount: true, EscapeRune: '\\'}
+		_, err := UnpackStream(&sb, strings.NewReader("я10000b"), opts)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("я", 10000)+"b", sb.String())
+	})
+
+	t.Run("huge expansion", func(t *testing.T) {
+		opts := Options{MultiDigitCount: true}
+		written, err := UnpackStream(io.Discard, strings.NewReader("a99999999"), opts)
+		require.NoError(t, err)
+		require.Equal(t, int64(99999999), written)
+	})
+}
+
+func TestUnpackStreamInvalidString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected SyntaxError
+	}{
+		{input: "3abc", expected: SyntaxError{Reason: ReasonLeadingDigit, Rune: '3', Pos: 0, Offset: 0}},
+		{input: "45", expected: SyntaxError{Reason: ReasonLeadingDigit, Rune: '4', Pos: 0, Offset: 0}},
+		{input: "aaa10b", expected: SyntaxError{Reason: ReasonConsecutiveDigits, Rune: '0', Pos: 4, Offset: 4}},
+		{input: "яя10b", expected: SyntaxError{Reason: ReasonConsecutiveDigits, Rune: '0', Pos: 3, Offset: 5}},
+		{input: `яя\`, expected: SyntaxError{Reason: ReasonDanglingEscape, Rune: '\\', Pos: 2, Offset: 4}},
+		{input: `qw\ne`, expected: SyntaxError{Reason: ReasonEscapedLetter, Rune: 'n', Pos: 3, Offset: 3}},
+		{input: "a٣", expected: SyntaxError{Reason: ReasonInvalidCount, Rune: '٣', Pos: 1, Offset: 1}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := UnpackStream(io.Discard, strings.NewReader(tc.input), DefaultOptions)
+			require.Truef(t, errors.Is(err, ErrInvalidString), "actual error %q", err)
+
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			require.Equal(t, tc.expected, *syntaxErr)
+		})
+	}
+
+	t.Run("max length", func(t *testing.T) {
+		_, err := UnpackStream(io.Discard, strings.NewReader("a5b"), Options{MaxLength: 5})
+		require.Truef(t, errors.Is(err, ErrMaxLengthExceeded), "actual error %q", err)
+	})
+
+	t.Run("long cluster", func(t *testing.T) {
+		opts := Options{Graphemes: true}
+		accents := strings.Repeat("\u0301", maxClusterExtenders)
+		var sb strings.Builder
+		_, err := UnpackStream(&sb, iotest.OneByteReader(strings.NewReader("a"+accents+"2")), opts)
+		require.NoError(t, err)
+		require.Equal(t, "a"+accents+"a"+accents, sb.String())
+
+		// Длинный кластер не буферизуется целиком: ошибка на первом лишнем знаке.
+		input := strings.NewReader("ya" + strings.Repeat("\u0301", 1<<20))
+		_, err = UnpackStream(io.Discard, iotest.OneByteReader(input), opts)
+		var syntaxErr *SyntaxError
+		require.Truef(t, errors.As(err, &syntaxErr), "actual error %q", err)
+		expected := SyntaxError{Reason: ReasonLongCluster, Rune: '\u0301', Pos: 32, Offset: 62}
+		require.Equal(t, expected, *syntaxErr)
+
+		_, err = UnpackWithOptions("ya"+accents+"\u0301", opts)
+		require.Truef(t, errors.As(err, &syntaxErr), "actual error %q", err)
+		require.Equal(t, expected, *syntaxErr)
+	})
+}
@@ -7,48 +7,120 @@ import (
 	"unicode"
 )
 
-var ErrInvalidString = errors.New("invalid string")
+var (
+	ErrInvalidString     = errors.New("invalid string")
+	ErrMaxLengthExceeded = errors.New("max expanded length exceeded")
+)
+
+// Options describes the grammar accepted by UnpackWithOptions.
+type Options struct {
+	// MultiDigitCount allows repeat counts of several digits, e.g. "a12".
+	MultiDigitCount bool
+	// EscapeRune escapes digits and itself, zero value disables escaping.
+	EscapeRune rune
+	// MaxLength limits the size of the result in bytes, zero value means no limit.
+	MaxLength int
+	// Graphemes repeats the whole extended grapheme cluster instead of its last rune,
+	// e.g. "e\u03013" gives three accented letters. A cluster of more than 31 runes is invalid.
+	Graphemes bool
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// DefaultOptions is the strict single-digit grammar used by Unpack.
+var DefaultOptions = Options{EscapeRune: '\\'}
 
 func Unpack(inStr string) (string, error) {
-	// Place your code here.
+	return UnpackWithOptions(inStr, DefaultOptions)
+}
+
+// UnpackWithOptions unpacks inStr according to the grammar described by opts.
+func UnpackWithOptions(inStr string, opts Options) (string, error) {
 	var sb strings.Builder
 	chars := []rune(inStr)
-	var err error
-	if len(chars) == 0 {
-		return "", nil
-	}
-	if unicode.IsDigit(chars[0]) {
-		return "", ErrInvalidString
-	}
 	num := len(chars)
-	isPrevEscapeCharacter := false
 	for i := 0; i < num; i++ {
-		curr := string(chars[i])
-		const escapeCharacter = 92
-		switch {
-		case chars[i] == escapeCharacter && !isPrevEscapeCharacter:
-			isPrevEscapeCharacter = true
-		case i < (num-1) && unicode.IsDigit(chars[i+1]):
-			if IsNextDigital(i, num, chars) {
-				return "", ErrInvalidString
+		curr := chars[i]
+		// Цифра, не съеденная как счётчик повтора, - ошибка.
+		if unicode.IsDigit(curr) {
+			if i == 0 {
+				return "", newSyntaxError(chars, i, ReasonLeadingDigit)
+			}
+			return "", newSyntaxError(chars, i, ReasonConsecutiveDigits)
+		}
+		if opts.EscapeRune != 0 && curr == opts.EscapeRune {
+			if i == num-1 {
+				return "", newSyntaxError(chars, i, ReasonDanglingEscape)
 			}
+			i++
+			curr = chars[i]
+			if !unicode.IsDigit(curr) && curr != opts.EscapeRune {
+				return "", newSyntaxError(chars, i, ReasonEscapedLetter)
+			}
+		}
 
-			next := string(chars[i+1])
-			repeatNum, _ := strconv.Atoi(next)
-			if repeatNum != 0 {
-				sb.WriteString(strings.Repeat(curr, repeatNum))
+		unit := string(curr)
+		if opts.Graphemes {
+			clusterEnd := i + 1
+			state := newClusterState(curr)
+			for clusterEnd < num && state.extends(chars[clusterEnd]) {
+				if clusterEnd-i > maxClusterExtenders {
+					return "", newSyntaxError(chars, clusterEnd, ReasonLongCluster)
+				}
+				clusterEnd++
 			}
+			unit = string(chars[i:clusterEnd])
+			i = clusterEnd - 1
+		}
 
-			i++
-		default:
+		repeatNum := 1
+		countEnd := i + 1
+		for countEnd < num && unicode.IsDigit(chars[countEnd]) && (opts.MultiDigitCount || countEnd == i+1) {
+			countEnd++
+		}
+		if countEnd > i+1 {
+			var err error
+			repeatNum, err = strconv.Atoi(string(chars[i+1 : countEnd]))
+			if err != nil {
+				return "", newSyntaxError(chars, invalidCountPos(chars, i+1, countEnd), ReasonInvalidCount)
+			}
+			i = countEnd - 1
+		}
 
-			sb.WriteString(curr)
-			isPrevEscapeCharacter = false
+		if err := checkLength(sb.Len(), len(unit), repeatNum, opts.MaxLength); err != nil {
+			return "", err
 		}
+		sb.WriteString(strings.Repeat(unit, repeatNum))
+	}
+	return sb.String(), nil
+}
+
+// invalidCountPos returns the position of the first non-ASCII digit of the count,
+// or the beginning of the count if it is just too large.
+func invalidCountPos(chars []rune, start, end int) int {
+	for pos := start; pos < end; pos++ {
+		if chars[pos] < '0' || chars[pos] > '9' {
+			return pos
+		}
+	}
+	return start
+}
+
+// checkLength reports whether repeatNum copies of unitLen bytes still fit into maxLength bytes.
+// Without the limit it only protects strings.Repeat from int overflow.
+func checkLength(written int, unitLen int, repeatNum int, maxLength int) error {
+	if maxLength <= 0 {
+		maxLength = maxInt
+	}
+	if repeatNum > (maxLength-written)/unitLen {
+		return ErrMaxLengthExceeded
 	}
-	return sb.String(), err
+	return nil
 }
 
+// IsNextDigital reports whether the rune after the next one is a digit.
+//
+// Deprecated: Unpack no longer uses it, it is kept for compatibility.
 func IsNextDigital(i int, num int, chars []rune) bool {
 	if i < num-2 {
 		return unicode.IsDigit(chars[i+2])
@@ -2,6 +2,7 @@ package hw02unpackstring
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -36,7 +37,7 @@ func TestUnpack(t *testing.T) {
 }
 
 func TestUnpackInvalidString(t *testing.T) {
-	invalidStrings := []string{"3abc", "45", "aaa10b"}
+	invalidStrings := []string{"3abc", "45", "aaa10b", `qw\ne`, `abc\`}
 	for _, tc := range invalidStrings {
 		tc := tc
 		t.Run(tc, func(t *testing.T) {
@@ -45,3 +46,152 @@ func TestUnpackInvalidString(t *testing.T) {
 		})
 	}
 }
+
+func TestUnpackSyntaxError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected SyntaxError
+	}{
+		{input: "3abc", expected: SyntaxError{Reason: ReasonLeadingDigit, Rune: '3', Pos: 0, Offset: 0}},
+		{input: "aaa10b", expected: SyntaxError{Reason: ReasonConsecutiveDigits, Rune: '0', Pos: 4, Offset: 4}},
+		{input: "яя10b", expected: SyntaxError{Reason: ReasonConsecutiveDigits, Rune: '0', Pos: 3, Offset: 5}},
+		{input: `abc\`, expected: SyntaxError{Reason: ReasonDanglingEscape, Rune: '\\', Pos: 3, Offset: 3}},
+		{input: `qw\ne`, expected: SyntaxError{Reason: ReasonEscapedLetter, Rune: 'n', Pos: 3, Offset: 3}},
+		{input: "a٣", expected: SyntaxError{Reason: ReasonInvalidCount, Rune: '٣', Pos: 1, Offset: 1}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Unpack(tc.input)
+			require.Truef(t, errors.Is(err, ErrInvalidString), "actual error %q", err)
+
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			require.Equal(t, tc.expected, *syntaxErr)
+		})
+	}
+
+	t.Run("message", func(t *testing.T) {
+		_, err := Unpack(`qw\ne`)
+		require.EqualError(t, err, `invalid string: escaped letter 'n' at position 3`)
+	})
+}
+
+func TestUnpackWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		expected string
+	}{
+		{name: "defaults", input: `a4bc2d5e\3`, opts: DefaultOptions, expected: "aaaabccddddde3"},
+		{
+			name:     "multi digit",
+			input:    "a12b",
+			opts:     Options{MultiDigitCount: true, EscapeRune: '\\'},
+			expected: "aaaaaaaaaaaab",
+		},
+		{name: "multi digit zero", input: "a00b", opts: Options{MultiDigitCount: true}, expected: "b"},
+		{
+			name:     "escaped multi digit",
+			input:    `\12\3`,
+			opts:     Options{MultiDigitCount: true, EscapeRune: '\\'},
+			expected: "113",
+		},
+		{name: "custom escape", input: `#45\2##`, opts: Options{EscapeRune: '#'}, expected: `44444\\#`},
+		{name: "no escape", input: `\2a`, opts: Options{}, expected: `\\a`},
+		{name: "within limit", input: "a5b", opts: Options{MaxLength: 6}, expected: "aaaaab"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := UnpackWithOptions(tc.input, tc.opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestUnpackWithOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		expected error
+	}{
+		{name: "defaults", input: "aaa10b", opts: DefaultOptions, expected: ErrInvalidString},
+		{name: "leading digit", input: "12a", opts: Options{MultiDigitCount: true}, expected: ErrInvalidString},
+		{
+			name:     "count overflow",
+			input:    "a99999999999999999999999",
+			opts:     Options{MultiDigitCount: true},
+			expected: ErrInvalidString,
+		},
+		{name: "custom escaped letter", input: "#a", opts: Options{EscapeRune: '#'}, expected: ErrInvalidString},
+		{name: "limit", input: "a5b", opts: Options{MaxLength: 5}, expected: ErrMaxLengthExceeded},
+		{name: "limit with wide runes", input: "я3", opts: Options{MaxLength: 5}, expected: ErrMaxLengthExceeded},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnpackWithOptions(tc.input, tc.opts)
+			require.Truef(t, errors.Is(err, tc.expected), "actual error %q", err)
+		})
+	}
+}
+
+func TestUnpackGraphemes(t *testing.T) {
+	opts := Options{EscapeRune: '\\', Graphemes: true}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain runes", input: "a4bc2d5e", expected: "aaaabccddddde"},
+		{name: "combining accent", input: "e\u03013x", expected: "e\u0301e\u0301e\u0301x"},
+		{name: "several combining marks", input: "a\u0308\u03012", expected: "a\u0308\u0301a\u0308\u0301"},
+		{
+			name:     "skin tone modifier",
+			input:    "\U0001F44D\U0001F3FD3",
+			expected: "\U0001F44D\U0001F3FD\U0001F44D\U0001F3FD\U0001F44D\U0001F3FD",
+		},
+		{
+			name:     "zwj sequence",
+			input:    "\U0001F468\u200D\U0001F469\u200D\U0001F4672",
+			expected: "\U0001F468\u200D\U0001F469\u200D\U0001F467\U0001F468\u200D\U0001F469\u200D\U0001F467",
+		},
+		{name: "flag", input: "\U0001F1F7\U0001F1FA2", expected: "\U0001F1F7\U0001F1FA\U0001F1F7\U0001F1FA"},
+		{
+			name:     "two flags",
+			input:    "\U0001F1F7\U0001F1FA\U0001F1FA\U0001F1F82",
+			expected: "\U0001F1F7\U0001F1FA\U0001F1FA\U0001F1F8\U0001F1FA\U0001F1F8",
+		},
+		{name: "escaped keycap", input: "\\3\uFE0F\u20E32", expected: "3\uFE0F\u20E33\uFE0F\u20E3"},
+		{name: "crlf", input: "\r\n2", expected: "\r\n\r\n"},
+		{name: "control breaks", input: "\n\u03012", expected: "\n\u0301\u0301"},
+		{name: "zero count", input: "e\u03010x", expected: "x"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := UnpackWithOptions(tc.input, opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+
+			var sb strings.Builder
+			_, err = UnpackStream(&sb, strings.NewReader(tc.input), opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, sb.String())
+		})
+	}
+
+	t.Run("without option", func(t *testing.T) {
+		result, err := Unpack("e\u03013")
+		require.NoError(t, err)
+		require.Equal(t, "e\u0301\u0301\u0301", result)
+	})
+}
@@ -0,0 +1,167 @@
+package hw03frequencyanalysis
+
+import (
+	"container/heap"
+	"io"
+	"math"
+	"sort"
+)
+
+// ApproxWordCount is an estimated word count, the real count lies in [Count-Error, Count].
+type ApproxWordCount struct {
+	WordCount
+	Error int
+}
+
+// ApproxCounter finds the most frequent words of an unbounded text using the Space-Saving
+// algorithm: it keeps no more than capacity words, so the memory doesn't grow with vocabulary.
+// Every word occurring more than ErrorBound() times is guaranteed to be kept.
+// ApproxCounter isn't safe for concurrent use.
+type ApproxCounter struct {
+	pipeline Pipeline
+	capacity int
+	items    map[string]*approxItem
+	heap     approxHeap
+	total    int
+	evicted  bool
+}
+
+type approxItem struct {
+	word  string
+	count int
+	error int
+	index int
+}
+
+const (
+	maxInt = int(^uint(0) >> 1)
+	// maxPreallocated limits memory allocated in advance, large counters grow as words come.
+	maxPreallocated = 1 << 16
+)
+
+// NewApproxCounter creates a counter keeping at most capacity words,
+// capacity less than 1 is treated as 1.
+func NewApproxCounter(p Pipeline, capacity int) *ApproxCounter {
+	if capacity < 1 {
+		capacity = 1
+	}
+	prealloc := capacity
+	if prealloc > maxPreallocated {
+		prealloc = maxPreallocated
+	}
+	return &ApproxCounter{
+		pipeline: p,
+		capacity: capacity,
+		items:    make(map[string]*approxItem, prealloc),
+		heap:     make(approxHeap, 0, prealloc),
+	}
+}
+
+// CapacityForError returns the capacity at which counts are overestimated
+// by no more than the fraction epsilon of all words.
+// Epsilon not greater than zero (or NaN) demands exact counts, so the capacity is unbounded.
+func CapacityForError(epsilon float64) int {
+	if !(epsilon > 0) {
+		return maxInt
+	}
+	capacity := math.Ceil(1 / epsilon)
+	if capacity >= float64(maxInt) {
+		return maxInt
+	}
+	return int(capacity)
+}
+
+// Add counts words of text, the same rules as for Counter.Add apply.
+func (c *ApproxCounter) Add(text string) {
+	for _, word := range c.pipeline.Words(text) {
+		c.addWord(word)
+	}
+}
+
+// ReadFrom counts words read from r until EOF.
+func (c *ApproxCounter) ReadFrom(r io.Reader) (int64, error) {
+	return readChunks(r, c.Add)
+}
+
+func (c *ApproxCounter) addWord(word string) {
+	c.total++
+	if item, ok := c.items[word]; ok {
+		item.count++
+		heap.Fix(&c.heap, item.index)
+		return
+	}
+	if len(c.heap) < c.capacity {
+		item := &approxItem{word: word, count: 1}
+		c.items[word] = item
+		heap.Push(&c.heap, item)
+		return
+	}
+	// Вытесняем самое редкое слово, новое наследует его счётчик как погрешность.
+	item := c.heap[0]
+	c.evicted = true
+	delete(c.items, item.word)
+	item.word = word
+	item.error = item.count
+	item.count++
+	c.items[word] = item
+	heap.Fix(&c.heap, 0)
+}
+
+// TopN returns n words with the highest estimated counts.
+func (c *ApproxCounter) TopN(n int) []ApproxWordCount {
+	if n <= 0 || len(c.heap) == 0 {
+		return nil
+	}
+	result := make([]ApproxWordCount, 0, len(c.heap))
+	for _, item := range c.heap {
+		result = append(result, ApproxWordCount{
+			WordCount: WordCount{Word: item.word, Count: item.count},
+			Error:     item.error,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return moreFrequent(result[i].WordCount, result[j].WordCount)
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// Total returns the number of counted words.
+func (c *ApproxCounter) Total() int {
+	return c.total
+}
+
+// ErrorBound returns the maximum overestimation of any kept count, which is also
+// the maximum real count of a dropped word. It never exceeds Total()/capacity.
+func (c *ApproxCounter) ErrorBound() int {
+	if !c.evicted {
+		return 0
+	}
+	return c.heap[0].count
+}
+
+// approxHeap is a min-heap of kept words ordered by count.
+type approxHeap []*approxItem
+
+func (h approxHeap) Len() int           { return len(h) }
+func (h approxHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h approxHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *approxHeap) Push(x interface{}) {
+	item := x.(*approxItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *approxHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,91 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproxCounter(t *testing.T) {
+	exact := NewCounter(DefaultPipeline)
+	exact.Add(text)
+
+	t.Run("enough capacity is exact", func(t *testing.T) {
+		c := NewApproxCounter(DefaultPipeline, exact.Distinct())
+		c.Add(text)
+
+		top := c.TopN(20)
+		for i, wc := range exact.TopN(20) {
+			require.Equal(t, wc, top[i].WordCount)
+			require.Zero(t, top[i].Error)
+		}
+		require.Zero(t, c.ErrorBound())
+	})
+
+	t.Run("bounded capacity", func(t *testing.T) {
+		const capacity = 40
+		c := NewApproxCounter(DefaultPipeline, capacity)
+		_, err := c.ReadFrom(strings.NewReader(text))
+		require.NoError(t, err)
+		require.Equal(t, exact.Total(), c.Total())
+		require.LessOrEqual(t, len(c.items), capacity)
+
+		for _, estimate := range c.TopN(capacity) {
+			exactCount := exact.counts[estimate.Word]
+			require.LessOrEqual(t, estimate.Error, c.ErrorBound())
+			require.LessOrEqual(t, c.ErrorBound(), c.Total()/capacity)
+			require.GreaterOrEqual(t, estimate.Count, exactCount, estimate.Word)
+			require.LessOrEqual(t, estimate.Count-estimate.Error, exactCount, estimate.Word)
+		}
+
+		// Слова, встречающиеся чаще границы погрешности, обязаны остаться.
+		for _, wc := range exact.TopN(exact.Distinct()) {
+			if wc.Count > c.ErrorBound() {
+				require.Contains(t, c.items, wc.Word)
+			}
+		}
+	})
+
+	t.Run("heavy hitter in noise", func(t *testing.T) {
+		var sb strings.Builder
+		for i := 0; i < 1000; i++ {
+			sb.WriteString("hot ")
+			sb.WriteString(strings.Repeat("x", i%97+1))
+			sb.WriteString(" ")
+		}
+		c := NewApproxCounter(DefaultPipeline, CapacityForError(0.05))
+		c.Add(sb.String())
+
+		top := c.TopN(1)
+		require.Equal(t, "hot", top[0].Word)
+		require.LessOrEqual(t, top[0].Count-top[0].Error, 1000)
+		require.GreaterOrEqual(t, top[0].Count, 1000)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Nil(t, NewApproxCounter(DefaultPipeline, 10).TopN(10))
+	})
+
+	t.Run("invalid capacity", func(t *testing.T) {
+		for _, capacity := range []int{0, -5} {
+			c := NewApproxCounter(DefaultPipeline, capacity)
+			c.Add("aaa bbb bbb")
+			require.Equal(t, []ApproxWordCount{{WordCount: WordCount{Word: "bbb", Count: 3}, Error: 1}}, c.TopN(5))
+		}
+	})
+
+	t.Run("capacity for error", func(t *testing.T) {
+		require.Equal(t, 20, CapacityForError(0.05))
+		require.Equal(t, 1, CapacityForError(2))
+		require.Equal(t, maxInt, CapacityForError(0))
+		require.Equal(t, maxInt, CapacityForError(-0.1))
+		require.Equal(t, maxInt, CapacityForError(math.NaN()))
+		require.Equal(t, maxInt, CapacityForError(1e-300))
+
+		c := NewApproxCounter(DefaultPipeline, CapacityForError(0))
+		c.Add("aaa bbb bbb")
+		require.Equal(t, []ApproxWordCount{{WordCount: WordCount{Word: "bbb", Count: 2}}}, c.TopN(1))
+	})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	top "github.com/novopashinwm/OtusGoLang/hw03_frequency_analysis"
+)
+
+const (
+	successCode = 0
+	ioErrorCode = 1
+	usageCode   = 2
+)
+
+var (
+	n                   int
+	caseSensitive       bool
+	stopLists, stopFile string
+	format              string
+)
+
+func init() {
+	flag.IntVar(&n, "n", 10, "number of words to show")
+	flag.BoolVar(&caseSensitive, "case", false, "count words differing in case separately")
+	flag.StringVar(&stopLists, "stop", "", "comma separated bundled stop-word lists: ru, en")
+	flag.StringVar(&stopFile, "stop-file", "", "file with stop words, one per line")
+	flag.StringVar(&format, "format", "table", "output format: table, json or csv")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file or directory ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	if n < 0 {
+		fmt.Fprintf(os.Stderr, "invalid -n %d: must not be negative\n", n)
+		flag.Usage()
+		os.Exit(usageCode)
+	}
+	write, ok := formats[format]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", format)
+		os.Exit(usageCode)
+	}
+	pipeline, err := buildPipeline()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(usageCode)
+	}
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{stdinName}
+	}
+	counter := top.NewCounter(pipeline)
+	if err := CountPaths(counter, paths, os.Stdin); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ioErrorCode)
+	}
+	if err := write(os.Stdout, NewReport(counter, n)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(ioErrorCode)
+	}
+	os.Exit(successCode)
+}
+
+func buildPipeline() (top.Pipeline, error) {
+	pipeline := top.Pipeline{Tokenizer: top.WordTokenizer{KeepHyphenated: true}}
+	if !caseSensitive {
+		pipeline.Normalizers = append(pipeline.Normalizers, top.CaseFold)
+	}
+
+	stopWords := top.NewStopWords()
+	for _, name := range strings.Split(stopLists, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "ru":
+			stopWords = stopWords.Union(top.RussianStopWords)
+		case "en":
+			stopWords = stopWords.Union(top.EnglishStopWords)
+		default:
+			return pipeline, fmt.Errorf("unknown stop-word list %q", name)
+		}
+	}
+	if stopFile != "" {
+		f, err := os.Open(stopFile)
+		if err != nil {
+			return pipeline, err
+		}
+		defer f.Close()
+		fileWords, err := top.ReadStopWords(f)
+		if err != nil {
+			return pipeline, err
+		}
+		stopWords = stopWords.Union(fileWords)
+	}
+	if len(stopWords) > 0 {
+		pipeline.Normalizers = append(pipeline.Normalizers, stopWords)
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	top "github.com/novopashinwm/OtusGoLang/hw03_frequency_analysis"
+)
+
+const stdinName = "-"
+
+// CountPaths counts words of files, of all regular files under directories
+// and of stdin for "-". Symlinks and pipes given as paths are read, other
+// special files are reported as errors.
+func CountPaths(counter *top.Counter, paths []string, stdin io.Reader) error {
+	for _, path := range paths {
+		if path == stdinName {
+			if _, err := counter.ReadFrom(stdin); err != nil {
+				return err
+			}
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if !isReadable(info.Mode()) {
+				return fmt.Errorf("%s: %w", path, errNotRegular)
+			}
+			if err := countFile(counter, path); err != nil {
+				return err
+			}
+			continue
+		}
+		// Внутри каталогов читаем только обычные файлы, ссылки не разыменовываем.
+		err = filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.Type().IsRegular() {
+				return err
+			}
+			return countFile(counter, name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errNotRegular = errors.New("not a regular file")
+
+// isReadable reports whether a file of the mode may be read as text: it is a regular file,
+// a pipe like the one made by the shell for <(cmd) or a character device.
+func isReadable(mode fs.FileMode) bool {
+	return mode.IsRegular() || mode&(fs.ModeNamedPipe|fs.ModeCharDevice) != 0
+}
+
+func countFile(counter *top.Counter, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := counter.ReadFrom(f); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+type Report struct {
+	Total    int          `json:"total"`
+	Distinct int          `json:"distinct"`
+	Words    []ReportWord `json:"words"`
+}
+
+type ReportWord struct {
+	Rank  int    `json:"rank"`
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+func NewReport(counter *top.Counter, n int) Report {
+	topWords := counter.TopN(n)
+	report := Report{
+		Total:    counter.Total(),
+		Distinct: counter.Distinct(),
+		Words:    make([]ReportWord, 0, len(topWords)),
+	}
+	for i, wc := range topWords {
+		report.Words = append(report.Words, ReportWord{Rank: i + 1, Word: wc.Word, Count: wc.Count})
+	}
+	return report
+}
+
+var formats = map[string]func(w io.Writer, report Report) error{
+	"table": writeTable,
+	"json":  writeJSON,
+	"csv":   writeCSV,
+}
+
+func writeTable(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tWORD\tCOUNT")
+	for _, rw := range report.Words {
+		fmt.Fprintf(tw, "%d\t%s\t%d\n", rw.Rank, rw.Word, rw.Count)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "total: %d, distinct: %d\n", report.Total, report.Distinct)
+	return err
+}
+
+func writeJSON(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// writeCSV writes ranked words, totals follow them as rows with rank "total" and "distinct".
+func writeCSV(w io.Writer, report Report) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{{"rank", "word", "count"}}
+	for _, rw := range report.Words {
+		records = append(records, []string{strconv.Itoa(rw.Rank), rw.Word, strconv.Itoa(rw.Count)})
+	}
+	records = append(records,
+		[]string{"total", "", strconv.Itoa(report.Total)},
+		[]string{"distinct", "", strconv.Itoa(report.Distinct)},
+	)
+	return cw.WriteAll(records)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	top "github.com/novopashinwm/OtusGoLang/hw03_frequency_analysis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountPaths(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("Кот и кот."), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("кот пёс"), 0o600))
+
+	pipeline := top.Pipeline{Tokenizer: top.WordTokenizer{}, Normalizers: []top.Normalizer{top.CaseFold}}
+
+	t.Run("directory and stdin", func(t *testing.T) {
+		counter := top.NewCounter(pipeline)
+		require.NoError(t, CountPaths(counter, []string{dir, stdinName}, strings.NewReader("пёс")))
+		expected := []top.WordCount{{Word: "кот", Count: 3}, {Word: "пёс", Count: 2}, {Word: "и", Count: 1}}
+		require.Equal(t, expected, counter.TopN(10))
+	})
+
+	t.Run("symlinked file", func(t *testing.T) {
+		link := filepath.Join(t.TempDir(), "link.txt")
+		require.NoError(t, os.Symlink(filepath.Join(dir, "a.txt"), link))
+
+		counter := top.NewCounter(pipeline)
+		require.NoError(t, CountPaths(counter, []string{link}, nil))
+		require.Equal(t, []top.WordCount{{Word: "кот", Count: 2}, {Word: "и", Count: 1}}, counter.TopN(10))
+	})
+
+	t.Run("socket", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "top.sock")
+		listener, err := net.Listen("unix", name)
+		if err != nil {
+			t.Skip("unix sockets are not supported:", err)
+		}
+		defer listener.Close()
+
+		err = CountPaths(top.NewCounter(pipeline), []string{name}, nil)
+		require.Truef(t, errors.Is(err, errNotRegular), "actual error %q", err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		counter := top.NewCounter(pipeline)
+		require.Error(t, CountPaths(counter, []string{filepath.Join(dir, "missing")}, nil))
+	})
+}
+
+func TestNewReport(t *testing.T) {
+	counter := top.NewCounter(top.DefaultPipeline)
+	counter.Add("b a b c")
+	require.Empty(t, NewReport(counter, -1).Words)
+	require.Len(t, NewReport(counter, 100).Words, 3)
+}
+
+func TestFormats(t *testing.T) {
+	counter := top.NewCounter(top.DefaultPipeline)
+	counter.Add("b a b c")
+	report := NewReport(counter, 2)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format:   "table",
+			expected: "RANK  WORD  COUNT\n1     b     2\n2     a     1\ntotal: 4, distinct: 3\n",
+		},
+		{
+			format: "json",
+			expected: `{
+  "total": 4,
+  "distinct": 3,
+  "words": [
+    {
+      "rank": 1,
+      "word": "b",
+      "count": 2
+    },
+    {
+      "rank": 2,
+      "word": "a",
+      "count": 1
+    }
+  ]
+}
+`,
+		},
+		{
+			format:   "csv",
+			expected: "rank,word,count\n1,b,2\n2,a,1\ntotal,,4\ndistinct,,3\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, formats[tc.format](&out, report))
+			require.Equal(t, tc.expected, out.String())
+		})
+	}
+}
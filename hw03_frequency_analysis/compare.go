@@ -0,0 +1,102 @@
+package hw03frequencyanalysis
+
+import (
+	"math"
+	"sort"
+)
+
+// WordChange describes how the frequency of a word changed between two texts.
+type WordChange struct {
+	Word          string
+	Before, After int
+	// Delta is After - Before.
+	Delta int
+	// Ratio is the change of the word share in the texts, so texts of different size
+	// can be compared: 2 means the word became twice as frequent. It is 0 if the word
+	// occurs in one text only.
+	Ratio float64
+}
+
+// Comparison holds word frequency changes between two texts.
+type Comparison struct {
+	changes []WordChange
+	added   []WordCount
+	removed []WordCount
+}
+
+// Compare compares word frequencies counted by before and after.
+func Compare(before, after *Counter) Comparison {
+	var cmp Comparison
+	for word, count := range before.counts {
+		change := WordChange{Word: word, Before: count, After: after.counts[word]}
+		if change.After == 0 {
+			cmp.removed = append(cmp.removed, WordCount{Word: word, Count: count})
+		}
+		cmp.changes = append(cmp.changes, change)
+	}
+	for word, count := range after.counts {
+		if _, ok := before.counts[word]; !ok {
+			cmp.added = append(cmp.added, WordCount{Word: word, Count: count})
+			cmp.changes = append(cmp.changes, WordChange{Word: word, After: count})
+		}
+	}
+	for i := range cmp.changes {
+		change := &cmp.changes[i]
+		change.Delta = change.After - change.Before
+		if change.Before > 0 && change.After > 0 {
+			change.Ratio = (float64(change.After) / float64(after.total)) /
+				(float64(change.Before) / float64(before.total))
+		}
+	}
+	sort.Sort(wordCounts(cmp.added))
+	sort.Sort(wordCounts(cmp.removed))
+	return cmp
+}
+
+// TopByDelta returns n words with the greatest change of count in either direction.
+func (cmp Comparison) TopByDelta(n int) []WordChange {
+	return cmp.top(n, nil, func(change WordChange) float64 {
+		return math.Abs(float64(change.Delta))
+	})
+}
+
+// TopByRatio returns n words occurring in both texts with the greatest change of share
+// in either direction: a word that became twice as rare goes along with one that became twice as frequent.
+func (cmp Comparison) TopByRatio(n int) []WordChange {
+	return cmp.top(n, func(change WordChange) bool { return change.Ratio > 0 }, func(change WordChange) float64 {
+		return math.Abs(math.Log(change.Ratio))
+	})
+}
+
+// Added returns words occurring only in the second text, the most frequent first.
+func (cmp Comparison) Added() []WordCount {
+	return cmp.added
+}
+
+// Removed returns words occurring only in the first text, the most frequent first.
+func (cmp Comparison) Removed() []WordCount {
+	return cmp.removed
+}
+
+func (cmp Comparison) top(n int, filter func(WordChange) bool, score func(WordChange) float64) []WordChange {
+	if n <= 0 {
+		return nil
+	}
+	result := make([]WordChange, 0, len(cmp.changes))
+	for _, change := range cmp.changes {
+		if filter == nil || filter(change) {
+			result = append(result, change)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		si, sj := score(result[i]), score(result[j])
+		if si != sj {
+			return si > sj
+		}
+		return result[i].Word < result[j].Word
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package hw03frequencyanalysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	before := NewCounter(DefaultPipeline)
+	before.Add("error error error timeout login login retry")
+	after := NewCounter(DefaultPipeline)
+	after.Add("error login login login login timeout timeout crash crash crash crash crash crash crash")
+
+	cmp := Compare(before, after)
+
+	t.Run("by delta", func(t *testing.T) {
+		expected := []WordChange{
+			{Word: "crash", Before: 0, After: 7, Delta: 7},
+			{Word: "error", Before: 3, After: 1, Delta: -2, Ratio: 1.0 / 6},
+			{Word: "login", Before: 2, After: 4, Delta: 2, Ratio: 1},
+		}
+		top := cmp.TopByDelta(3)
+		require.Len(t, top, 3)
+		for i := range expected {
+			require.Equal(t, expected[i].Word, top[i].Word)
+			require.Equal(t, expected[i].Before, top[i].Before)
+			require.Equal(t, expected[i].After, top[i].After)
+			require.Equal(t, expected[i].Delta, top[i].Delta)
+			require.InDelta(t, expected[i].Ratio, top[i].Ratio, 1e-9)
+		}
+	})
+
+	t.Run("by ratio", func(t *testing.T) {
+		// Второй текст вдвое длиннее, поэтому login по доле не изменился.
+		top := cmp.TopByRatio(10)
+		words := make([]string, 0, len(top))
+		for _, change := range top {
+			words = append(words, change.Word)
+		}
+		require.Equal(t, []string{"error", "login", "timeout"}, words)
+		require.InDelta(t, 1.0/6, top[0].Ratio, 1e-9)
+		require.InDelta(t, 1.0, top[1].Ratio, 1e-9)
+		require.InDelta(t, 1.0, top[2].Ratio, 1e-9)
+	})
+
+	t.Run("one side only", func(t *testing.T) {
+		require.Equal(t, []WordCount{{Word: "crash", Count: 7}}, cmp.Added())
+		require.Equal(t, []WordCount{{Word: "retry", Count: 1}}, cmp.Removed())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		empty := Compare(NewCounter(DefaultPipeline), NewCounter(DefaultPipeline))
+		require.Empty(t, empty.TopByDelta(5))
+		require.Empty(t, empty.TopByRatio(5))
+		require.Empty(t, empty.Added())
+		require.Nil(t, cmp.TopByDelta(0))
+	})
+}
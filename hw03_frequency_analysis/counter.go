@@ -0,0 +1,101 @@
+package hw03frequencyanalysis
+
+import (
+	"bufio"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxChunkSize limits the text kept in memory by Counter.ReadFrom,
+// a longer run of text without white space makes it fail with bufio.ErrTooLong.
+const MaxChunkSize = 1 << 20
+
+// Counter accumulates word frequencies of a text fed piece by piece, the text itself isn't kept.
+// Counter isn't safe for concurrent use.
+type Counter struct {
+	pipeline Pipeline
+	counts   map[string]int
+	total    int
+}
+
+func NewCounter(p Pipeline) *Counter {
+	return &Counter{
+		pipeline: p,
+		counts:   make(map[string]int),
+	}
+}
+
+// Add counts words of text. Pieces of a text may be added separately if they are split
+// on white space, the pipeline tokenizer must not join tokens across it.
+func (c *Counter) Add(text string) {
+	for _, word := range c.pipeline.Words(text) {
+		c.addWord(word)
+	}
+}
+
+func (c *Counter) addWord(word string) {
+	c.counts[word]++
+	c.total++
+}
+
+// ReadFrom counts words read from r until EOF.
+func (c *Counter) ReadFrom(r io.Reader) (int64, error) {
+	return readChunks(r, c.Add)
+}
+
+// Merge adds counts of other to c.
+func (c *Counter) Merge(other *Counter) {
+	for word, count := range other.counts {
+		c.counts[word] += count
+	}
+	c.total += other.total
+}
+
+// TopN returns n most frequent words counted so far.
+func (c *Counter) TopN(n int) []WordCount {
+	return topCounts(c.counts, n)
+}
+
+// Total returns the number of counted words.
+func (c *Counter) Total() int {
+	return c.total
+}
+
+// Distinct returns the number of different counted words.
+func (c *Counter) Distinct() int {
+	return len(c.counts)
+}
+
+// readChunks passes text read from r to add in chunks ending with white space.
+func readChunks(r io.Reader, add func(text string)) (int64, error) {
+	var read int64
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxChunkSize)
+	scanner.Split(scanChunks)
+	for scanner.Scan() {
+		chunk := scanner.Text()
+		read += int64(len(chunk))
+		add(chunk)
+	}
+	return read, scanner.Err()
+}
+
+// scanChunks is a bufio.SplitFunc returning as much text as possible ending with white space,
+// so no word is cut in two.
+func scanChunks(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF {
+		if len(data) == 0 {
+			return 0, nil, nil
+		}
+		return len(data), data, nil
+	}
+	for end := len(data); end > 0; {
+		char, size := utf8.DecodeLastRune(data[:end])
+		if unicode.IsSpace(char) {
+			return end, data[:end], nil
+		}
+		end -= size
+	}
+	return 0, nil, nil
+}
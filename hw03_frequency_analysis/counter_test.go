@@ -0,0 +1,60 @@
+package hw03frequencyanalysis
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	t.Run("read from", func(t *testing.T) {
+		c := NewCounter(DefaultPipeline)
+		read, err := c.ReadFrom(strings.NewReader(text))
+		require.NoError(t, err)
+		require.Equal(t, int64(len(text)), read)
+		require.Equal(t, TopN(text, 20), c.TopN(20))
+	})
+
+	t.Run("one byte reads", func(t *testing.T) {
+		c := NewCounter(Pipeline{Tokenizer: WordTokenizer{KeepHyphenated: true}, Normalizers: []Normalizer{CaseFold}})
+		_, err := c.ReadFrom(iotest.OneByteReader(strings.NewReader(text)))
+		require.NoError(t, err)
+
+		expected := NewCounter(c.pipeline)
+		expected.Add(text)
+		require.Equal(t, expected.TopN(100), c.TopN(100))
+		require.Equal(t, expected.Total(), c.Total())
+	})
+
+	t.Run("add and merge", func(t *testing.T) {
+		first := NewCounter(DefaultPipeline)
+		first.Add("cat and dog ")
+		first.Add("and one cat")
+
+		second := NewCounter(DefaultPipeline)
+		second.Add("Cat; cat! dog")
+
+		first.Merge(second)
+		expected := []WordCount{{Word: "cat", Count: 4}, {Word: "and", Count: 2}, {Word: "dog", Count: 2}}
+		require.Equal(t, expected, first.TopN(3))
+		require.Equal(t, 9, first.Total())
+		require.Equal(t, 4, first.Distinct())
+	})
+
+	t.Run("too long word", func(t *testing.T) {
+		c := NewCounter(DefaultPipeline)
+		_, err := c.ReadFrom(strings.NewReader(strings.Repeat("a", MaxChunkSize+1)))
+		require.True(t, errors.Is(err, bufio.ErrTooLong))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		c := NewCounter(DefaultPipeline)
+		_, err := c.ReadFrom(strings.NewReader(""))
+		require.NoError(t, err)
+		require.Nil(t, c.TopN(10))
+	})
+}
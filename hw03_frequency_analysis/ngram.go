@@ -0,0 +1,59 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TopNGrams returns n most frequent phrases of size consecutive words.
+func TopNGrams(inStr string, size, n int) []WordCount {
+	return DefaultPipeline.TopNGrams(inStr, size, n)
+}
+
+// TopNGrams returns n most frequent phrases of size consecutive words of inStr,
+// words are joined with a single space. Phrases don't cross sentence boundaries,
+// a word dropped by the pipeline doesn't break a phrase.
+func (p Pipeline) TopNGrams(inStr string, size, n int) []WordCount {
+	if inStr == "" || size < 1 {
+		return nil
+	}
+	counter := NewCounter(p)
+	for _, sentence := range splitSentences(inStr) {
+		words := p.Words(sentence)
+		for i := 0; i+size <= len(words); i++ {
+			counter.addWord(strings.Join(words[i:i+size], " "))
+		}
+	}
+	return counter.TopN(n)
+}
+
+// splitSentences cuts text after every run of sentence terminators followed by white space.
+func splitSentences(text string) []string {
+	var sentences []string
+	chars := []rune(text)
+	start := 0
+	for i := 0; i < len(chars); i++ {
+		if !isSentenceEnd(chars[i]) {
+			continue
+		}
+		for i+1 < len(chars) && isSentenceEnd(chars[i+1]) {
+			i++
+		}
+		if i+1 == len(chars) || unicode.IsSpace(chars[i+1]) {
+			sentences = append(sentences, string(chars[start:i+1]))
+			start = i + 1
+		}
+	}
+	if start < len(chars) {
+		sentences = append(sentences, string(chars[start:]))
+	}
+	return sentences
+}
+
+func isSentenceEnd(char rune) bool {
+	switch char {
+	case '.', '!', '?', '…':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package hw03frequencyanalysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopNGrams(t *testing.T) {
+	t.Run("bigrams", func(t *testing.T) {
+		expected := []WordCount{
+			{Word: "кристофер робин", Count: 4},
+			{Word: "а если", Count: 2},
+			{Word: "вы знаете", Count: 2},
+			{Word: "если ты", Count: 2},
+			{Word: "ты просто", Count: 2},
+		}
+		require.Equal(t, expected, TopNGrams(text, 2, 5))
+	})
+
+	t.Run("sentence boundaries", func(t *testing.T) {
+		const input = "Reset the password. Reset the password! The password? reset... the Password"
+		expected := []WordCount{
+			{Word: "the password", Count: 4},
+			{Word: "reset the", Count: 2},
+		}
+		require.Equal(t, expected, TopNGrams(input, 2, 2))
+
+		top := TopNGrams(input, 2, 10)
+		for _, wc := range top {
+			require.NotEqual(t, "password reset", wc.Word)
+			require.NotEqual(t, "password the", wc.Word)
+		}
+	})
+
+	t.Run("trigrams", func(t *testing.T) {
+		const input = "не могу войти в систему. Снова не могу войти! не могу"
+		expected := []WordCount{
+			{Word: "не могу войти", Count: 2},
+			{Word: "войти в систему", Count: 1},
+		}
+		require.Equal(t, expected, TopNGrams(input, 3, 2))
+	})
+
+	t.Run("unigrams are words", func(t *testing.T) {
+		require.Equal(t, TopN(text, 10), TopNGrams(text, 1, 10))
+	})
+
+	t.Run("dot inside word doesn't end sentence", func(t *testing.T) {
+		require.Equal(t, []string{"released v1.2", "v1.2 today"}, wordsOf(TopNGrams("released v1.2 today", 2, 5)))
+	})
+
+	t.Run("not enough words", func(t *testing.T) {
+		require.Nil(t, TopNGrams("one two", 3, 5))
+		require.Nil(t, TopNGrams("", 2, 5))
+		require.Nil(t, TopNGrams(text, 0, 5))
+	})
+}
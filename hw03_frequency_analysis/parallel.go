@@ -0,0 +1,83 @@
+package hw03frequencyanalysis
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TopNParallel works like TopN but counts words in several goroutines.
+func TopNParallel(inStr string, n, workers int) []WordCount {
+	return DefaultPipeline.TopNParallel(inStr, n, workers)
+}
+
+// minPartSize is the smallest part worth a goroutine: smaller parts are counted
+// faster than their counters are merged.
+const minPartSize = 64 << 10
+
+// TopNParallel splits inStr on white space into parts counted by workers goroutines
+// and merges their counts, the result is the same as of TopN.
+// Workers are limited by GOMAXPROCS and by the text size, so a short text or
+// a single CPU is counted sequentially.
+func (p Pipeline) TopNParallel(inStr string, n, workers int) []WordCount {
+	if inStr == "" {
+		return nil
+	}
+	if procs := runtime.GOMAXPROCS(0); workers > procs {
+		workers = procs
+	}
+	if parts := len(inStr) / minPartSize; workers > parts {
+		workers = parts
+	}
+	if workers <= 1 {
+		return p.TopN(inStr, n)
+	}
+	return p.countParallel(inStr, workers).TopN(n)
+}
+
+// countParallel counts words of inStr split into workers parts.
+func (p Pipeline) countParallel(inStr string, workers int) *Counter {
+	parts := splitOnSpace(inStr, workers)
+	counters := make([]*Counter, len(parts))
+	wg := &sync.WaitGroup{}
+	for i, part := range parts {
+		wg.Add(1)
+		go func(i int, part string) {
+			defer wg.Done()
+			counters[i] = NewCounter(p)
+			counters[i].Add(part)
+		}(i, part)
+	}
+	wg.Wait()
+
+	for _, counter := range counters[1:] {
+		counters[0].Merge(counter)
+	}
+	return counters[0]
+}
+
+// splitOnSpace cuts text into at most parts pieces of about the same size,
+// every cut is made at white space.
+func splitOnSpace(text string, parts int) []string {
+	if parts < 1 {
+		parts = 1
+	}
+	chunks := make([]string, 0, parts)
+	partSize := len(text)/parts + 1
+	for len(text) > 0 {
+		if len(text) <= partSize {
+			chunks = append(chunks, text)
+			break
+		}
+		end := strings.IndexFunc(text[partSize:], unicode.IsSpace)
+		if end < 0 {
+			chunks = append(chunks, text)
+			break
+		}
+		end += partSize
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return chunks
+}
@@ -0,0 +1,65 @@
+package hw03frequencyanalysis
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopNParallel(t *testing.T) {
+	t.Run("same as sequential", func(t *testing.T) {
+		bigText := strings.Repeat(text+"\n", 50)
+		for _, workers := range []int{0, 1, 2, 3, 7, 16, 1000} {
+			require.Equal(t, TopN(bigText, 100), TopNParallel(bigText, 100, workers), "workers %d", workers)
+			// Проверяем и параллельный подсчёт, даже если процессор один.
+			require.Equal(t, TopN(bigText, 100), DefaultPipeline.countParallel(bigText, workers).TopN(100),
+				"workers %d", workers)
+		}
+	})
+
+	t.Run("word tokenizer", func(t *testing.T) {
+		pipeline := Pipeline{Tokenizer: WordTokenizer{}, Normalizers: []Normalizer{CaseFold}}
+		require.Equal(t, pipeline.TopN(text, 30), pipeline.TopNParallel(text, 30, 5))
+		require.Equal(t, pipeline.TopN(text, 30), pipeline.countParallel(text, 5).TopN(30))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Nil(t, TopNParallel("", 10, 4))
+	})
+}
+
+func TestSplitOnSpace(t *testing.T) {
+	require.Equal(t, []string{"aaa bbb", " ccc"}, splitOnSpace("aaa bbb ccc", 3))
+	require.Equal(t, []string{"aaaaaa", " b"}, splitOnSpace("aaaaaa b", 4))
+	require.Equal(t, []string{"aaaaaa"}, splitOnSpace("aaaaaa", 4))
+	require.Equal(t, []string{"яя яя", " яя"}, splitOnSpace("яя яя яя", 3))
+	require.Equal(t, strings.Repeat(text, 3), strings.Join(splitOnSpace(strings.Repeat(text, 3), 8), ""))
+}
+
+var benchText = strings.Repeat(text+"\n", 2000)
+
+func BenchmarkTopN(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		TopN(benchText, 10)
+	}
+}
+
+func BenchmarkTopNParallel(b *testing.B) {
+	if runtime.NumCPU() < 2 {
+		b.Skip("parallel counting needs at least 2 CPUs")
+	}
+	for _, procs := range []int{1, 2, 4, runtime.NumCPU()} {
+		if procs > runtime.NumCPU() {
+			continue
+		}
+		b.Run(strconv.Itoa(procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			for i := 0; i < b.N; i++ {
+				TopNParallel(benchText, 10, procs)
+			}
+		})
+	}
+}
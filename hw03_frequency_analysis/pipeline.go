@@ -0,0 +1,128 @@
+package hw03frequencyanalysis
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits text into raw tokens.
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// Normalizer turns a raw token into the word to count, empty result drops the token.
+type Normalizer interface {
+	Normalize(token string) string
+}
+
+type TokenizerFunc func(text string) []string
+
+func (f TokenizerFunc) Tokenize(text string) []string { return f(text) }
+
+type NormalizerFunc func(token string) string
+
+func (f NormalizerFunc) Normalize(token string) string { return f(token) }
+
+var trailingPunctuation = regexp.MustCompile(`^(.*)[\.\-\;\+\!\?\:]$`)
+
+var (
+	// WhitespaceTokenizer splits text around runs of white space.
+	WhitespaceTokenizer Tokenizer = TokenizerFunc(strings.Fields)
+
+	// CaseFold makes words differing only in case equal.
+	CaseFold Normalizer = NormalizerFunc(strings.ToLower)
+	// TrimTrailingPunctuation removes one trailing punctuation mark, a lone mark is dropped.
+	TrimTrailingPunctuation Normalizer = NormalizerFunc(func(token string) string {
+		return trailingPunctuation.ReplaceAllString(token, `$1`)
+	})
+	// TrimPunctuation removes punctuation and symbols from both ends: "(нога)," gives "нога".
+	TrimPunctuation Normalizer = NormalizerFunc(func(token string) string {
+		return strings.TrimFunc(token, isPunctuation)
+	})
+)
+
+// WordTokenizer splits text on Unicode word boundaries: a word is a run of letters,
+// digits and combining marks, apostrophes between letters don't break it.
+type WordTokenizer struct {
+	// KeepHyphenated keeps hyphenated words like "какой-то" as a single token.
+	KeepHyphenated bool
+}
+
+func (wt WordTokenizer) Tokenize(text string) []string {
+	var tokens []string
+	chars := []rune(text)
+	start := -1
+	for i, char := range chars {
+		inWord := isWordRune(char) ||
+			(start >= 0 && wt.isJoiner(char) && i+1 < len(chars) && isWordRune(chars[i+1]))
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			tokens = append(tokens, string(chars[start:i]))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, string(chars[start:]))
+	}
+	return tokens
+}
+
+func (wt WordTokenizer) isJoiner(char rune) bool {
+	switch char {
+	case '\'', '’':
+		return true
+	case '-', '\u2010':
+		return wt.KeepHyphenated
+	}
+	return false
+}
+
+func isWordRune(char rune) bool {
+	return unicode.In(char, unicode.L, unicode.N, unicode.M)
+}
+
+func isPunctuation(char rune) bool {
+	return unicode.IsPunct(char) || unicode.IsSymbol(char)
+}
+
+// Pipeline describes how text is turned into words.
+type Pipeline struct {
+	// Tokenizer is WhitespaceTokenizer if nil.
+	Tokenizer   Tokenizer
+	Normalizers []Normalizer
+}
+
+// DefaultPipeline is the pipeline used by Top10.
+var DefaultPipeline = Pipeline{
+	Tokenizer:   WhitespaceTokenizer,
+	Normalizers: []Normalizer{CaseFold, TrimTrailingPunctuation},
+}
+
+// Words returns normalized words of text in their original order.
+func (p Pipeline) Words(text string) []string {
+	tokenizer := p.Tokenizer
+	if tokenizer == nil {
+		tokenizer = WhitespaceTokenizer
+	}
+	tokens := tokenizer.Tokenize(text)
+	result := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if word := p.normalize(token); word != "" {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+func (p Pipeline) normalize(token string) string {
+	for _, normalizer := range p.Normalizers {
+		if token == "" {
+			break
+		}
+		token = normalizer.Normalize(token)
+	}
+	return token
+}
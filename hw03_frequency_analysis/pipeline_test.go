@@ -0,0 +1,85 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizers(t *testing.T) {
+	const input = "Винни-Пух сказал: \"Don't (стой) - иди!\" mp3"
+
+	t.Run("whitespace", func(t *testing.T) {
+		expected := []string{"Винни-Пух", "сказал:", "\"Don't", "(стой)", "-", "иди!\"", "mp3"}
+		require.Equal(t, expected, WhitespaceTokenizer.Tokenize(input))
+	})
+
+	t.Run("words", func(t *testing.T) {
+		expected := []string{"Винни", "Пух", "сказал", "Don't", "стой", "иди", "mp3"}
+		require.Equal(t, expected, WordTokenizer{}.Tokenize(input))
+	})
+
+	t.Run("hyphenated words", func(t *testing.T) {
+		expected := []string{"Винни-Пух", "сказал", "Don't", "стой", "иди", "mp3"}
+		require.Equal(t, expected, WordTokenizer{KeepHyphenated: true}.Tokenize(input))
+	})
+
+	t.Run("combining marks", func(t *testing.T) {
+		require.Equal(t, []string{"cafe\u0301", "ok"}, WordTokenizer{}.Tokenize("cafe\u0301, ok"))
+	})
+}
+
+func TestNormalizers(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalizer Normalizer
+		input      string
+		expected   string
+	}{
+		{name: "case fold", normalizer: CaseFold, input: "НоГа", expected: "нога"},
+		{name: "trailing", normalizer: TrimTrailingPunctuation, input: "нога!", expected: "нога"},
+		{name: "trailing comma kept", normalizer: TrimTrailingPunctuation, input: "нога,", expected: "нога,"},
+		{name: "trailing lone dash", normalizer: TrimTrailingPunctuation, input: "-", expected: ""},
+		{name: "both ends", normalizer: TrimPunctuation, input: "«(нога),»", expected: "нога"},
+		{name: "inner kept", normalizer: TrimPunctuation, input: "'какой-то'", expected: "какой-то"},
+		{name: "only punctuation", normalizer: TrimPunctuation, input: "--", expected: ""},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.normalizer.Normalize(tc.input))
+		})
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	t.Run("punctuation on both ends", func(t *testing.T) {
+		pipeline := Pipeline{
+			Tokenizer:   WhitespaceTokenizer,
+			Normalizers: []Normalizer{CaseFold, TrimPunctuation},
+		}
+		require.Equal(t, []string{"нога", "рука"}, pipeline.Top10("нога, 'Нога' (нога) - рука"))
+	})
+
+	t.Run("custom normalizer", func(t *testing.T) {
+		pipeline := Pipeline{
+			Tokenizer: WordTokenizer{},
+			Normalizers: []Normalizer{CaseFold, NormalizerFunc(func(token string) string {
+				return strings.TrimSuffix(token, "s")
+			})},
+		}
+		require.Equal(t, []string{"cat", "dog"}, pipeline.Top10("Cats, cat; dogs"))
+	})
+
+	t.Run("nil tokenizer", func(t *testing.T) {
+		pipeline := Pipeline{Normalizers: []Normalizer{CaseFold}}
+		require.Equal(t, []string{"b", "a"}, pipeline.Top10("a B A b b"))
+		require.Equal(t, []string{"a", "b", "b,"}, pipeline.Words("a b b,"))
+	})
+
+	t.Run("default is top10", func(t *testing.T) {
+		require.Equal(t, Top10(text), DefaultPipeline.Top10(text))
+	})
+}
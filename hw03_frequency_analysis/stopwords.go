@@ -0,0 +1,119 @@
+package hw03frequencyanalysis
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// StopWords is a Normalizer dropping the listed words, so it should follow CaseFold
+// and punctuation trimming in a pipeline.
+type StopWords map[string]struct{}
+
+func NewStopWords(words ...string) StopWords {
+	sw := make(StopWords, len(words))
+	for _, word := range words {
+		sw[word] = struct{}{}
+	}
+	return sw
+}
+
+// ReadStopWords reads a stop-word list with one word per line, empty lines
+// and lines starting with '#' are skipped.
+func ReadStopWords(r io.Reader) (StopWords, error) {
+	sw := make(StopWords)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		sw[word] = struct{}{}
+	}
+	return sw, scanner.Err()
+}
+
+// Union returns a list containing words of sw and all others.
+func (sw StopWords) Union(others ...StopWords) StopWords {
+	result := make(StopWords, len(sw))
+	for _, list := range append([]StopWords{sw}, others...) {
+		for word := range list {
+			result[word] = struct{}{}
+		}
+	}
+	return result
+}
+
+func (sw StopWords) Normalize(token string) string {
+	if _, ok := sw[token]; ok {
+		return ""
+	}
+	return token
+}
+
+var (
+	RussianStopWords = NewStopWords(
+		"а", "без", "более", "бы", "был", "была", "были", "было", "быть", "в", "вам", "вас",
+		"ведь", "во", "вот", "впрочем", "все", "всегда", "всего", "всех", "всю", "вы", "где",
+		"да", "даже", "для", "до", "другой", "его", "ее", "ей", "ему", "если", "есть", "еще",
+		"ж", "же", "за", "зачем", "здесь", "и", "из", "или", "им", "их", "к", "как", "какая",
+		"какой", "когда", "кто", "куда", "ли", "между", "меня", "мне", "много", "может", "мой",
+		"моя", "мы", "на", "над", "надо", "нас", "не", "него", "нее", "ней", "нельзя", "нет",
+		"ни", "нибудь", "никогда", "ним", "них", "ничего", "но", "ну", "о", "об", "он", "она",
+		"они", "опять", "от", "перед", "по", "под", "после", "потом", "потому", "почти", "при",
+		"про", "раз", "разве", "с", "сам", "свою", "себе", "себя", "сейчас", "со", "совсем",
+		"так", "такой", "там", "тебя", "тем", "теперь", "то", "тогда", "того", "тоже", "только",
+		"том", "тот", "тут", "ты", "у", "уж", "уже", "хоть", "чего", "чем", "через", "что",
+		"чтоб", "чтобы", "чуть", "эти", "этого", "этой", "этом", "этот", "эту", "я",
+	)
+	EnglishStopWords = NewStopWords(
+		"a", "about", "above", "after", "again", "against", "all", "am", "an", "and", "any",
+		"are", "as", "at", "be", "because", "been", "before", "being", "below", "between",
+		"both", "but", "by", "can", "did", "do", "does", "doing", "down", "during", "each",
+		"few", "for", "from", "further", "had", "has", "have", "having", "he", "her", "here",
+		"hers", "herself", "him", "himself", "his", "how", "i", "if", "in", "into", "is", "it",
+		"its", "itself", "just", "me", "more", "most", "my", "myself", "no", "nor", "not", "now",
+		"of", "off", "on", "once", "only", "or", "other", "our", "ours", "ourselves", "out",
+		"over", "own", "same", "she", "should", "so", "some", "such", "than", "that", "the",
+		"their", "theirs", "them", "themselves", "then", "there", "these", "they", "this",
+		"those", "through", "to", "too", "under", "until", "up", "very", "was", "we", "were",
+		"what", "when", "where", "which", "while", "who", "whom", "why", "will", "with", "you",
+		"your", "yours", "yourself", "yourselves",
+	)
+)
+
+// SuffixStemmer is a light stemmer cutting the longest known ending off a word,
+// unless less than MinStem runes are left. It is a Normalizer aggregating
+// inflected forms like "нога", "ноги", "ногу"; a real stemmer or lemmatizer
+// can be plugged into a pipeline the same way.
+type SuffixStemmer struct {
+	MinStem  int
+	Suffixes []string
+}
+
+func (s SuffixStemmer) Normalize(token string) string {
+	longest := ""
+	for _, suffix := range s.Suffixes {
+		if len(suffix) > len(longest) && strings.HasSuffix(token, suffix) &&
+			utf8.RuneCountInString(token)-utf8.RuneCountInString(suffix) >= s.MinStem {
+			longest = suffix
+		}
+	}
+	return token[:len(token)-len(longest)]
+}
+
+var (
+	RussianStemmer = SuffixStemmer{
+		MinStem: 3,
+		Suffixes: []string{
+			"ами", "ями", "ого", "его", "ому", "ему", "ыми", "ими", "ой", "ей", "ий", "ый",
+			"ая", "яя", "ое", "ее", "ые", "ие", "ов", "ев", "ах", "ях", "ам", "ям", "ом", "ем",
+			"а", "я", "о", "е", "ы", "и", "у", "ю", "ь",
+		},
+	}
+	EnglishStemmer = SuffixStemmer{
+		MinStem:  3,
+		Suffixes: []string{"ing", "ed", "es", "s", "ly"},
+	}
+)
@@ -0,0 +1,76 @@
+package hw03frequencyanalysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopWords(t *testing.T) {
+	t.Run("bundled lists", func(t *testing.T) {
+		pipeline := Pipeline{
+			Tokenizer:   WordTokenizer{KeepHyphenated: true},
+			Normalizers: []Normalizer{CaseFold, RussianStopWords},
+		}
+		expected := []string{
+			"кристофер", // 4
+			"робин",     // 4
+			"винни-пух", // 3
+			"имя",       // 3
+			"иногда",    // 3
+			"больше",    // 2
+			"винни",     // 2
+			"звал",      // 2
+			"знает",     // 2
+			"знаете",    // 2
+		}
+		require.Equal(t, expected, pipeline.Top10(text))
+	})
+
+	t.Run("user list", func(t *testing.T) {
+		userList, err := ReadStopWords(strings.NewReader("# служебные\nкот\n\n  пёс  \n"))
+		require.NoError(t, err)
+		require.Equal(t, NewStopWords("кот", "пёс"), userList)
+
+		pipeline := Pipeline{
+			Tokenizer:   WordTokenizer{},
+			Normalizers: []Normalizer{CaseFold, userList.Union(EnglishStopWords)},
+		}
+		require.Equal(t, []string{"мышь"}, pipeline.Top10("Кот the пёс, мышь"))
+	})
+}
+
+func TestSuffixStemmer(t *testing.T) {
+	tests := []struct {
+		stemmer  SuffixStemmer
+		input    string
+		expected string
+	}{
+		{stemmer: RussianStemmer, input: "нога", expected: "ног"},
+		{stemmer: RussianStemmer, input: "ноги", expected: "ног"},
+		{stemmer: RussianStemmer, input: "ногу", expected: "ног"},
+		{stemmer: RussianStemmer, input: "ногами", expected: "ног"},
+		{stemmer: RussianStemmer, input: "он", expected: "он"},
+		{stemmer: RussianStemmer, input: "имя", expected: "имя"},
+		{stemmer: EnglishStemmer, input: "cats", expected: "cat"},
+		{stemmer: EnglishStemmer, input: "walking", expected: "walk"},
+		{stemmer: EnglishStemmer, input: "is", expected: "is"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.stemmer.Normalize(tc.input))
+		})
+	}
+
+	t.Run("aggregated counts", func(t *testing.T) {
+		pipeline := Pipeline{
+			Tokenizer:   WordTokenizer{},
+			Normalizers: []Normalizer{CaseFold, RussianStopWords, RussianStemmer},
+		}
+		expected := []WordCount{{Word: "ног", Count: 3}, {Word: "рук", Count: 1}}
+		require.Equal(t, expected, pipeline.TopN("Нога, и ноги, и ногу в руке", 5))
+	})
+}
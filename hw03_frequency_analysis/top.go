@@ -1,66 +1,79 @@
 package hw03frequencyanalysis
 
-import (
-	"regexp"
-	"sort"
-	"strings"
-)
+import "sort"
 
-type Word struct {
-	word  string
-	count int
+// WordCount is a word with the number of its occurrences.
+type WordCount struct {
+	Word  string
+	Count int
 }
 
-type words []Word
+// Word is the former name of WordCount.
+//
+// Deprecated: use WordCount.
+type Word = WordCount
 
-func (s words) Len() int { return len(s) }
-func (s words) Less(i, j int) bool {
-	if s[i].count < s[j].count {
-		return true
-	}
-	if s[i].count == s[j].count {
-		return strings.Compare(s[i].word, s[j].word) == 1
+// wordCounts sorts by count descending, words with equal counts go in lexicographic order.
+type wordCounts []WordCount
+
+func (s wordCounts) Len() int           { return len(s) }
+func (s wordCounts) Less(i, j int) bool { return moreFrequent(s[i], s[j]) }
+
+func (s wordCounts) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func moreFrequent(a, b WordCount) bool {
+	if a.Count != b.Count {
+		return a.Count > b.Count
 	}
-	return false
+	return a.Word < b.Word
 }
 
-func (s words) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
-
 func Top10(inStr string) []string {
+	return DefaultPipeline.Top10(inStr)
+}
+
+// TopN returns n most frequent words of inStr with their counts.
+func TopN(inStr string, n int) []WordCount {
+	return DefaultPipeline.TopN(inStr, n)
+}
+
+// Top10 returns ten most frequent words of inStr split and normalized by the pipeline.
+func (p Pipeline) Top10(inStr string) []string {
+	return wordsOf(p.TopN(inStr, 10))
+}
+
+// TopN returns n most frequent words of inStr split and normalized by the pipeline.
+func (p Pipeline) TopN(inStr string, n int) []WordCount {
 	if inStr == "" {
 		return nil
 	}
-	re := regexp.MustCompile(`^(.*)[\.\-\;\+\!\?\:]$`)
-	arrSplit := strings.Fields(inStr)
-	cache := make(map[string]int)
-	for i := 0; i < len(arrSplit); i++ {
-		key := strings.ToLower(arrSplit[i])
-		key = re.ReplaceAllString(key, `$1`)
-		if key == "" {
-			continue
-		}
-		value, ok := cache[key]
-		if !ok {
-			cache[key] = 1
-		} else {
-			cache[key] = value + 1
-		}
-	}
+	counter := NewCounter(p)
+	counter.Add(inStr)
+	return counter.TopN(n)
+}
 
-	ws := make(words, 0)
+func topCounts(cache map[string]int, n int) []WordCount {
+	if n <= 0 || len(cache) == 0 {
+		return nil
+	}
+	ws := make(wordCounts, 0, len(cache))
 	for k, v := range cache {
-		ws = append(ws, Word{count: v, word: k})
+		ws = append(ws, WordCount{Word: k, Count: v})
+	}
+	sort.Sort(ws)
+	if len(ws) > n {
+		ws = ws[:n]
+	}
+	return ws
+}
+
+func wordsOf(counts []WordCount) []string {
+	if len(counts) == 0 {
+		return nil
 	}
-	sort.Sort(sort.Reverse(ws))
-	var arrRet []string
-	numTop10 := func() int {
-		if len(ws) > 10 {
-			return 10
-		}
-		return len(ws)
-	}()
-	for i := 0; i < numTop10; i++ {
-		arrRet = append(arrRet, ws[i].word)
+	arrRet := make([]string, 0, len(counts))
+	for _, wc := range counts {
+		arrRet = append(arrRet, wc.Word)
 	}
 	return arrRet
 }
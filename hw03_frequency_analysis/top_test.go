@@ -94,3 +94,40 @@ func TestTop10(t *testing.T) {
 		require.Equal(t, expected, Top10(newText))
 	})
 }
+
+func TestTopN(t *testing.T) {
+	t.Run("no words in empty string", func(t *testing.T) {
+		require.Len(t, TopN("", 5), 0)
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		expected := []WordCount{
+			{Word: "а", Count: 8},
+			{Word: "он", Count: 8},
+			{Word: "и", Count: 6},
+			{Word: "ты", Count: 5},
+			{Word: "что", Count: 5},
+		}
+		require.Equal(t, expected, TopN(text, 5))
+	})
+
+	t.Run("more than ten", func(t *testing.T) {
+		top := TopN(text, 50)
+		require.Len(t, top, 50)
+		require.Equal(t, Top10(text), wordsOf(top[:10]))
+		for i := 1; i < len(top); i++ {
+			require.True(t, top[i-1].Count > top[i].Count ||
+				(top[i-1].Count == top[i].Count && top[i-1].Word < top[i].Word))
+		}
+	})
+
+	t.Run("fewer words than n", func(t *testing.T) {
+		expected := []WordCount{{Word: "b", Count: 2}, {Word: "a", Count: 1}}
+		require.Equal(t, expected, TopN("b a b", 10))
+	})
+
+	t.Run("non-positive n", func(t *testing.T) {
+		require.Nil(t, TopN(text, 0))
+		require.Nil(t, TopN(text, -1))
+	})
+}
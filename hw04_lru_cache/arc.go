@@ -0,0 +1,119 @@
+package hw04lrucache
+
+const (
+	segmentRecent uint8 = iota
+	segmentFrequent
+)
+
+// arcPolicy is the Adaptive Replacement Cache by Megiddo and Modha. Entries used once
+// live in recent and move to frequent when used again, keys of entries evicted from
+// either list are remembered, and a hit on a remembered key shifts target, the share
+// of the capacity given to recent, towards the list it was evicted from.
+type arcPolicy[K comparable, V any] struct {
+	capacity       int
+	target         int
+	recent         list[cacheItem[K, V]]
+	frequent       list[cacheItem[K, V]]
+	recentGhosts   *ghostList[K]
+	frequentGhosts *ghostList[K]
+}
+
+func (p *arcPolicy[K, V]) add(ci cacheItem[K, V]) (item, victim *entry[K, V]) {
+	switch {
+	case p.recentGhosts.contains(ci.key):
+		p.target = min(p.capacity, p.target+max(p.frequentGhosts.len()/p.recentGhosts.len(), 1))
+		p.recentGhosts.remove(ci.key)
+		victim = p.replace(false)
+		ci.segment = segmentFrequent
+	case p.frequentGhosts.contains(ci.key):
+		p.target = max(0, p.target-max(p.recentGhosts.len()/p.frequentGhosts.len(), 1))
+		p.frequentGhosts.remove(ci.key)
+		victim = p.replace(true)
+		ci.segment = segmentFrequent
+	default:
+		recent := p.recent.Len() + p.recentGhosts.len()
+		total := recent + p.frequent.Len() + p.frequentGhosts.len()
+		switch {
+		case recent >= p.capacity && p.recent.Len() >= p.capacity:
+			victim = p.recent.Back()
+			p.recent.Remove(victim)
+		case recent >= p.capacity:
+			p.recentGhosts.removeOldest()
+			victim = p.replace(false)
+		case total >= p.capacity:
+			if total >= 2*p.capacity {
+				p.frequentGhosts.removeOldest()
+			}
+			victim = p.replace(false)
+		}
+		ci.segment = segmentRecent
+	}
+
+	if ci.segment == segmentFrequent {
+		return p.frequent.PushFront(ci), victim
+	}
+	return p.recent.PushFront(ci), victim
+}
+
+// replace evicts an entry if the cache is full and remembers its key, it is REPLACE in the paper.
+func (p *arcPolicy[K, V]) replace(frequentHit bool) *entry[K, V] {
+	if p.len() < p.capacity {
+		return nil
+	}
+	victim := p.choose(frequentHit)
+	p.remove(victim)
+	if victim.Value.segment == segmentRecent {
+		p.recentGhosts.push(victim.Value.key)
+	} else {
+		p.frequentGhosts.push(victim.Value.key)
+	}
+	return victim
+}
+
+func (p *arcPolicy[K, V]) touch(item *entry[K, V]) {
+	if item.Value.segment == segmentFrequent {
+		p.frequent.MoveToFront(item)
+		return
+	}
+	p.recent.Remove(item)
+	item.Value.segment = segmentFrequent
+	p.frequent.pushFrontItem(item)
+}
+
+func (p *arcPolicy[K, V]) remove(item *entry[K, V]) {
+	if item.Value.segment == segmentFrequent {
+		p.frequent.Remove(item)
+	} else {
+		p.recent.Remove(item)
+	}
+}
+
+func (p *arcPolicy[K, V]) victim() *entry[K, V] {
+	return p.choose(false)
+}
+
+// choose picks the list to evict from. After a hit on a key evicted from frequent
+// recent gives up an entry even if it holds exactly target ones.
+func (p *arcPolicy[K, V]) choose(frequentHit bool) *entry[K, V] {
+	r := p.recent.Len()
+	if r > 0 && (r > p.target || frequentHit && r == p.target || p.frequent.Len() == 0) {
+		return p.recent.Back()
+	}
+	return p.frequent.Back()
+}
+
+func (p *arcPolicy[K, V]) setCapacity(capacity int) {
+	p.capacity = capacity
+	p.target = min(p.target, capacity)
+	p.recentGhosts.setCapacity(capacity)
+	p.frequentGhosts.setCapacity(capacity)
+}
+
+func (p *arcPolicy[K, V]) len() int {
+	return p.recent.Len() + p.frequent.Len()
+}
+
+func (p *arcPolicy[K, V]) each(fn func(item *entry[K, V])) {
+	// Записи recent сверх target вытесняются первыми, остальные — когда frequent опустеет.
+	eachItemSplit(&p.recent, p.target, fn, &p.frequent)
+}
@@ -1,79 +1,335 @@
 package hw04lrucache
 
-import "sync"
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
 
 type Key string
 
-type Cache interface {
-	Set(key Key, value interface{}) bool
-	Get(key Key) (interface{}, bool)
+// TypedCache is a cache of values of type V accessed by keys of type K.
+type TypedCache[K comparable, V any] interface {
+	Set(key K, value V) bool
+	// SetWithTTL works like Set, but the entry expires after ttl, zero ttl means never.
+	SetWithTTL(key K, value V, ttl time.Duration) bool
+	// SetWithWeight works like Set, but the entry weighs weight instead of computed by Config.Weigher.
+	SetWithWeight(key K, value V, weight int64) bool
+	Get(key K) (V, bool)
+	// GetOrLoad returns the cached value or stores the value returned by loader.
+	// Concurrent calls for the same key wait for a single loader call, which is
+	// cancelled once all of them are cancelled by ctx.
+	GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error)
+	// Peek works like Get but doesn't mark the entry as recently used.
+	Peek(key K) (V, bool)
+	Contains(key K) bool
+	// Delete removes the entry and reports whether it was in the cache.
+	Delete(key K) bool
+	// Keys returns keys from the entry to be evicted last to the next one to be evicted,
+	// which is from the most to the least recently used for PolicyLRU.
+	Keys() []K
+	// Len returns the number of entries including expired but not yet removed ones.
+	Len() int
+	// Resize changes the capacity evicting entries chosen by the policy if needed,
+	// the number of evicted entries is returned.
+	Resize(capacity int) int
 	Clear()
+	Stats() Stats
+	// Snapshot writes the entries to w with Config.Codec.
+	Snapshot(w io.Writer) error
+	// Restore adds the entries written by Snapshot.
+	Restore(r io.Reader) error
+	Close()
 }
 
-type lruCache struct {
-	capacity int
-	queue    List
-	items    map[Key]*ListItem
-	mutex    *sync.Mutex
+// Cache keeps the untyped API of the cache.
+type Cache = TypedCache[Key, interface{}]
+
+type lruCache[K comparable, V any] struct {
+	capacity   int
+	maxWeight  int64
+	weight     int64
+	weigher    func(key K, value V) int64
+	errorTTL   time.Duration
+	calls      map[K]*loadCall[V]
+	failures   map[K]loadFailure
+	kind       Policy
+	codec      Codec
+	policy     evictionPolicy[K, V]
+	items      map[K]*TypedListItem[cacheItem[K, V]]
+	mutex      *sync.Mutex
+	defaultTTL time.Duration
+	clock      Clock
+	onEvict    func(key K, value V, reason EvictReason)
+	evicted    []evictedItem[K, V]
+	stats      Stats
+	stop       chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func (cache *lruCache[K, V]) Set(key K, value V) bool {
+	return cache.SetWithTTL(key, value, cache.defaultTTL)
 }
 
-func (cache *lruCache) Set(key Key, value interface{}) bool {
+func (cache *lruCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
 	cache.mutex.Lock()
-	defer cache.mutex.Unlock()
+	defer cache.unlock()
 
-	ci := &cacheItem{key: key, value: value}
-	if item, ok := cache.items[key]; ok {
-		item.Value = ci
-		cache.queue.MoveToFront(item)
-		return true
+	return cache.set(key, value, cache.expiresAt(ttl), cache.weigh(key, value))
+}
+
+func (cache *lruCache[K, V]) SetWithWeight(key K, value V, weight int64) bool {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	return cache.set(key, value, cache.expiresAt(cache.defaultTTL), weight)
+}
+
+// weigh returns the weight of an entry added without explicit one.
+func (cache *lruCache[K, V]) weigh(key K, value V) int64 {
+	if cache.maxWeight <= 0 || cache.weigher == nil {
+		return 1
 	}
+	return cache.weigher(key, value)
+}
 
-	if cache.queue.Len() == cache.capacity {
-		lastItem, ok := cache.queue.Back().Value.(*cacheItem)
-		if !ok {
-			return false
+// set stores the value of key, the loader call and the error of key become outdated.
+func (cache *lruCache[K, V]) set(key K, value V, expiresAt time.Time, weight int64) bool {
+	delete(cache.calls, key)
+	delete(cache.failures, key)
+	if cache.maxWeight <= 0 {
+		weight = 0
+	}
+	item, ok := cache.items[key]
+	if ok && weight <= item.Value.weight {
+		cache.report(item.Value, EvictReplaced)
+		cache.weight += weight - item.Value.weight
+		item.Value.value = value
+		item.Value.expiresAt = expiresAt
+		item.Value.weight = weight
+		cache.policy.touch(item)
+		return true
+	}
+	if cache.maxWeight > 0 && (weight < 0 || weight > cache.maxWeight) {
+		cache.stats.Rejections++
+		if ok {
+			// The old value must not outlive the rejected one.
+			cache.removeItem(item, EvictReplaced)
 		}
-		cache.queue.Remove(cache.queue.Back())
-		delete(cache.items, lastItem.key)
+		return ok
+	}
+	if ok {
+		// A heavier value is added anew, so room is made for it without evicting the entry itself.
+		cache.removeItem(item, EvictReplaced)
+	}
+	if cache.capacity <= 0 {
+		return ok
+	}
+	for cache.maxWeight > 0 && cache.weight+weight > cache.maxWeight {
+		cache.removeItem(cache.policy.victim(), EvictCapacity)
 	}
-	pushFront := cache.queue.PushFront(ci)
-	cache.items[key] = pushFront
-	return false
+	item, victim := cache.policy.add(cacheItem[K, V]{key: key, value: value, expiresAt: expiresAt, weight: weight})
+	cache.items[key] = item
+	cache.weight += weight
+	if victim != nil {
+		cache.dropItem(victim, EvictCapacity)
+	}
+	return ok
 }
 
-func (cache *lruCache) Get(key Key) (interface{}, bool) {
+func (cache *lruCache[K, V]) Get(key K) (V, bool) {
 	cache.mutex.Lock()
-	defer cache.mutex.Unlock()
+	defer cache.unlock()
 
+	return cache.get(key)
+}
+
+func (cache *lruCache[K, V]) get(key K) (V, bool) {
+	var zero V
 	if item, ok := cache.items[key]; ok {
-		cache.queue.MoveToFront(item)
-		ci, ok := item.Value.(*cacheItem)
-		if !ok {
-			return nil, false
+		if cache.expired(item) {
+			cache.removeItem(item, EvictExpired)
+			cache.stats.Misses++
+			return zero, false
 		}
-		return ci.value, true
+		cache.policy.touch(item)
+		cache.stats.Hits++
+		return item.Value.value, true
+	}
+	cache.stats.Misses++
+	return zero, false
+}
+
+func (cache *lruCache[K, V]) Peek(key K) (V, bool) {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	if item := cache.liveItem(key); item != nil {
+		return item.Value.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (cache *lruCache[K, V]) Contains(key K) bool {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	return cache.liveItem(key) != nil
+}
+
+// liveItem returns the item of key unless it is missing or expired.
+func (cache *lruCache[K, V]) liveItem(key K) *TypedListItem[cacheItem[K, V]] {
+	item, ok := cache.items[key]
+	if !ok {
+		return nil
+	}
+	if cache.expired(item) {
+		cache.removeItem(item, EvictExpired)
+		return nil
+	}
+	return item
+}
+
+func (cache *lruCache[K, V]) Delete(key K) bool {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	delete(cache.calls, key)
+	delete(cache.failures, key)
+	item, ok := cache.items[key]
+	if ok {
+		cache.removeItem(item, EvictDeleted)
 	}
-	return nil, false
+	return ok
 }
 
-func (cache *lruCache) Clear() {
+func (cache *lruCache[K, V]) Keys() []K {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
 
-	cache.queue = NewList()
-	cache.items = make(map[Key]*ListItem, cache.capacity)
+	keys := make([]K, 0, cache.policy.len())
+	cache.policy.each(func(item *TypedListItem[cacheItem[K, V]]) {
+		if !cache.expired(item) {
+			keys = append(keys, item.Value.key)
+		}
+	})
+	return keys
 }
 
-type cacheItem struct {
-	key   Key
-	value interface{}
+func (cache *lruCache[K, V]) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.policy.len()
 }
 
-func NewCache(capacity int) Cache {
-	return &lruCache{
-		capacity: capacity,
-		queue:    NewList(),
-		items:    make(map[Key]*ListItem, capacity),
-		mutex:    new(sync.Mutex),
+func (cache *lruCache[K, V]) Resize(capacity int) int {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	cache.capacity = capacity
+	cache.policy.setCapacity(capacity)
+	evicted := 0
+	for cache.policy.len() > max(capacity, 0) {
+		cache.removeItem(cache.policy.victim(), EvictCapacity)
+		evicted++
+	}
+	return evicted
+}
+
+func (cache *lruCache[K, V]) Clear() {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	if cache.onEvict != nil {
+		start := len(cache.evicted)
+		cache.policy.each(func(item *TypedListItem[cacheItem[K, V]]) {
+			cache.evicted = append(cache.evicted, evictedItem[K, V]{item: item.Value, reason: EvictCleared})
+		})
+		slices.Reverse(cache.evicted[start:])
+	}
+	cache.policy = newPolicy[K, V](cache.kind, cache.capacity)
+	cache.items = make(map[K]*TypedListItem[cacheItem[K, V]], cache.capacity)
+	cache.weight = 0
+	cache.calls = make(map[K]*loadCall[V])
+	cache.failures = make(map[K]loadFailure)
+}
+
+func (cache *lruCache[K, V]) removeItem(item *TypedListItem[cacheItem[K, V]], reason EvictReason) {
+	cache.policy.remove(item)
+	cache.dropItem(item, reason)
+}
+
+// dropItem evicts the item already removed from the policy.
+func (cache *lruCache[K, V]) dropItem(item *TypedListItem[cacheItem[K, V]], reason EvictReason) {
+	cache.forget(item)
+	if reason == EvictCapacity || reason == EvictExpired {
+		cache.stats.Evictions++
 	}
+	cache.report(item.Value, reason)
+}
+
+// report queues the entry for OnEvict called by unlock.
+func (cache *lruCache[K, V]) report(ci cacheItem[K, V], reason EvictReason) {
+	if cache.onEvict != nil {
+		cache.evicted = append(cache.evicted, evictedItem[K, V]{item: ci, reason: reason})
+	}
+}
+
+// forget drops the item already removed from the policy without reporting it.
+func (cache *lruCache[K, V]) forget(item *TypedListItem[cacheItem[K, V]]) {
+	delete(cache.items, item.Value.key)
+	cache.weight -= item.Value.weight
+}
+
+// cacheItem is stored in the list by value, so an entry costs a single allocation.
+type cacheItem[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	weight    int64
+	// segment and uses are kept by the eviction policy.
+	segment uint8
+	uses    int
+}
+
+func NewTypedCache[K comparable, V any](capacity int) TypedCache[K, V] {
+	return NewTypedCacheWithConfig(Config[K, V]{Capacity: capacity})
+}
+
+func NewTypedCacheWithConfig[K comparable, V any](cfg Config[K, V]) TypedCache[K, V] {
+	cache := &lruCache[K, V]{
+		capacity:   cfg.Capacity,
+		maxWeight:  cfg.MaxWeight,
+		weigher:    cfg.Weigher,
+		errorTTL:   cfg.ErrorTTL,
+		calls:      make(map[K]*loadCall[V]),
+		failures:   make(map[K]loadFailure),
+		kind:       cfg.Policy,
+		codec:      cfg.Codec,
+		policy:     newPolicy[K, V](cfg.Policy, cfg.Capacity),
+		items:      make(map[K]*TypedListItem[cacheItem[K, V]], cfg.Capacity),
+		mutex:      new(sync.Mutex),
+		defaultTTL: cfg.DefaultTTL,
+		clock:      cfg.Clock,
+		onEvict:    cfg.OnEvict,
+	}
+	if cache.clock == nil {
+		cache.clock = systemClock{}
+	}
+	if cache.codec == nil {
+		cache.codec = GobCodec
+	}
+	if cfg.JanitorInterval > 0 {
+		cache.startJanitor(cfg.JanitorInterval)
+	}
+	return cache
+}
+
+func NewCache(capacity int) Cache {
+	return NewTypedCache[Key, interface{}](capacity)
 }
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -87,8 +88,6 @@ func TestCache(t *testing.T) {
 }
 
 func TestCacheMultithreading(t *testing.T) {
-	t.Skip() // Remove me if task with asterisk completed.
-
 	c := NewCache(10)
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
@@ -109,3 +108,160 @@ func TestCacheMultithreading(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestCacheOperations(t *testing.T) {
+	t.Run("peek doesn't promote", func(t *testing.T) {
+		c := NewCache(2)
+		c.Set("aaa", 1)
+		c.Set("bbb", 2)
+
+		val, ok := c.Peek("aaa")
+		require.True(t, ok)
+		require.Equal(t, 1, val)
+		_, ok = c.Peek("ccc")
+		require.False(t, ok)
+
+		c.Set("ccc", 3) // aaa осталась самой старой
+		require.False(t, c.Contains("aaa"))
+		require.True(t, c.Contains("bbb"))
+		require.Equal(t, Stats{Evictions: 1, Size: 2}, c.Stats())
+	})
+
+	t.Run("keys in recency order", func(t *testing.T) {
+		c := NewCache(5)
+		require.Empty(t, c.Keys())
+		c.Set("aaa", 1)
+		c.Set("bbb", 2)
+		c.Set("ccc", 3)
+		c.Get("aaa")
+		require.Equal(t, []Key{"aaa", "ccc", "bbb"}, c.Keys())
+		require.Equal(t, 3, c.Len())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c := NewCache(5)
+		c.Set("aaa", 1)
+		require.True(t, c.Delete("aaa"))
+		require.False(t, c.Contains("aaa"))
+		require.Equal(t, 0, c.Len())
+	})
+
+	t.Run("expired entries", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 5, Clock: clock})
+		c.SetWithTTL("aaa", 1, time.Second)
+		c.Set("bbb", 2)
+		clock.Advance(time.Second)
+
+		require.Equal(t, []string{"bbb"}, c.Keys())
+		require.Equal(t, 2, c.Len())
+		_, ok := c.Peek("aaa")
+		require.False(t, ok)
+		require.Equal(t, 1, c.Len())
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		c := NewCache(4)
+		for i, key := range []Key{"aaa", "bbb", "ccc", "ddd"} {
+			c.Set(key, i)
+		}
+		require.Equal(t, 0, c.Resize(5))
+		require.Equal(t, 2, c.Resize(2))
+		require.Equal(t, []Key{"ddd", "ccc"}, c.Keys())
+
+		c.Set("eee", 4)
+		require.Equal(t, []Key{"eee", "ddd"}, c.Keys())
+
+		require.Equal(t, 2, c.Resize(0))
+		require.False(t, c.Set("fff", 5))
+		require.Equal(t, 0, c.Len())
+
+		c.Resize(1)
+		c.Set("fff", 5)
+		require.Equal(t, []Key{"fff"}, c.Keys())
+	})
+}
+
+func TestCacheOperationsMultithreading(t *testing.T) {
+	c := NewTypedCache[int, int](100)
+	wg := &sync.WaitGroup{}
+	operations := []func(i int){
+		func(i int) { c.Set(i%300, i) },
+		func(i int) { c.Get(i % 300) },
+		func(i int) { c.Peek(i % 300) },
+		func(i int) { c.Contains(i % 300) },
+		func(i int) { c.Delete(i % 300) },
+		func(i int) { c.Keys() },
+		func(i int) { c.Len() },
+		func(i int) { c.Resize(50 + i%100) },
+		func(i int) { c.Stats() },
+	}
+	for _, op := range operations {
+		wg.Add(1)
+		go func(op func(i int)) {
+			defer wg.Done()
+			for i := 0; i < 10_000; i++ {
+				op(i)
+			}
+		}(op)
+	}
+	wg.Wait()
+
+	keys := c.Keys()
+	require.Equal(t, len(keys), c.Len())
+	require.LessOrEqual(t, len(keys), 150)
+}
+
+func TestTypedCache(t *testing.T) {
+	c := NewTypedCache[int, string](2)
+
+	require.False(t, c.Set(1, "one"))
+	require.False(t, c.Set(2, "two"))
+
+	val, ok := c.Get(1)
+	require.True(t, ok)
+	require.Equal(t, "one", val)
+
+	require.False(t, c.Set(3, "three")) // вытесняет 2
+	_, ok = c.Get(2)
+	require.False(t, ok)
+
+	require.True(t, c.Set(1, "uno"))
+	val, ok = c.Get(1)
+	require.True(t, ok)
+	require.Equal(t, "uno", val)
+
+	c.Clear()
+	val, ok = c.Get(1)
+	require.False(t, ok)
+	require.Equal(t, "", val)
+}
+
+type benchValue struct {
+	id, size int
+}
+
+func BenchmarkCache(b *testing.B) {
+	keys := make([]Key, 1024)
+	for i := range keys {
+		keys[i] = Key(strconv.Itoa(i))
+	}
+
+	b.Run("untyped", func(b *testing.B) {
+		b.ReportAllocs()
+		c := NewCache(512)
+		for i := 0; i < b.N; i++ {
+			c.Set(keys[i%len(keys)], benchValue{id: i, size: i})
+			c.Get(keys[(i*7)%len(keys)])
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		b.ReportAllocs()
+		c := NewTypedCache[Key, benchValue](512)
+		for i := 0; i < b.N; i++ {
+			c.Set(keys[i%len(keys)], benchValue{id: i, size: i})
+			c.Get(keys[(i*7)%len(keys)])
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package hw04lrucache
+
+import "time"
+
+// Config describes a cache created by NewTypedCacheWithConfig.
+type Config[K comparable, V any] struct {
+	Capacity int
+	// MaxWeight enables the weight limit: entries chosen by the policy are evicted until
+	// the total weight of entries fits MaxWeight, and entries heavier than MaxWeight or with
+	// negative weight are not stored. Capacity still limits the number of entries.
+	MaxWeight int64
+	// Weigher computes the weight of entries added by Set and SetWithTTL, they weigh 1 if nil.
+	// It is called with the cache locked, so it must not use the cache.
+	Weigher func(key K, value V) int64
+	// ErrorTTL makes GetOrLoad remember loader errors for this time instead of calling
+	// the loader for every request, zero value means errors are not remembered.
+	// No more errors than Capacity are remembered, others are not until some of them expire.
+	ErrorTTL time.Duration
+	// Policy chooses entries to evict when the cache is full, PolicyLRU by default.
+	Policy Policy
+	// DefaultTTL is the lifetime of entries added by Set, zero value means forever.
+	DefaultTTL time.Duration
+	// JanitorInterval enables a goroutine removing expired entries with this period,
+	// otherwise they are only removed on access. The goroutine is stopped by Close.
+	JanitorInterval time.Duration
+	// Clock is used to expire entries, the system clock if nil.
+	Clock Clock
+	// Codec serializes entries for Snapshot and Restore, GobCodec if nil.
+	Codec Codec
+	// OnEvict is called for every entry leaving the cache and for every replaced value.
+	// It is called after the cache is unlocked, so it may use the cache,
+	// but calls from different goroutines may run concurrently.
+	OnEvict func(key K, value V, reason EvictReason)
+}
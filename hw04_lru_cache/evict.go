@@ -0,0 +1,74 @@
+package hw04lrucache
+
+import "fmt"
+
+// EvictReason tells why an entry left the cache.
+type EvictReason int
+
+const (
+	// EvictCapacity - the entry chosen by the policy made room for a new one.
+	EvictCapacity EvictReason = iota + 1
+	// EvictExpired - the entry outlived its TTL.
+	EvictExpired
+	// EvictDeleted - the entry was removed by Delete.
+	EvictDeleted
+	// EvictCleared - the entry was removed by Clear.
+	EvictCleared
+	// EvictReplaced - the value was replaced by Set or Restore, even if the new value
+	// was rejected as too heavy. The key stays in the cache unless the new value is rejected.
+	EvictReplaced
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	case EvictCleared:
+		return "cleared"
+	case EvictReplaced:
+		return "replaced"
+	}
+	return fmt.Sprintf("EvictReason(%d)", int(r))
+}
+
+// Stats describes how the cache has been used since its creation.
+type Stats struct {
+	Hits, Misses uint64
+	// Evictions counts entries removed because of capacity or TTL.
+	Evictions uint64
+	// Rejections counts entries not stored because of their weight.
+	Rejections uint64
+	Size       int
+	// Weight is the total weight of entries, it is zero without Config.MaxWeight.
+	Weight int64
+}
+
+type evictedItem[K comparable, V any] struct {
+	item   cacheItem[K, V]
+	reason EvictReason
+}
+
+func (cache *lruCache[K, V]) Stats() Stats {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	stats := cache.stats
+	stats.Size = cache.policy.len()
+	stats.Weight = cache.weight
+	return stats
+}
+
+// unlock releases the cache mutex and then reports entries evicted while it was held.
+func (cache *lruCache[K, V]) unlock() {
+	evicted := cache.evicted
+	cache.evicted = nil
+	cache.mutex.Unlock()
+
+	for _, e := range evicted {
+		cache.onEvict(e.item.key, e.item.value, e.reason)
+	}
+}
@@ -0,0 +1,121 @@
+package hw04lrucache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type evictRecord struct {
+	key    string
+	value  int
+	reason EvictReason
+}
+
+func TestOnEvict(t *testing.T) {
+	var records []evictRecord
+	clock := newFakeClock()
+	c := NewTypedCacheWithConfig(Config[string, int]{
+		Capacity: 2,
+		Clock:    clock,
+		OnEvict: func(key string, value int, reason EvictReason) {
+			records = append(records, evictRecord{key: key, value: value, reason: reason})
+		},
+	})
+
+	c.Set("aaa", 1)
+	c.Set("bbb", 2)
+	c.Set("aaa", 10) // старое значение сообщается как замещённое
+	c.Set("ccc", 3)  // вытесняет bbb
+	require.Equal(t, []evictRecord{
+		{key: "aaa", value: 1, reason: EvictReplaced},
+		{key: "bbb", value: 2, reason: EvictCapacity},
+	}, records)
+
+	c.SetWithTTL("ddd", 4, time.Second) // вытесняет aaa
+	clock.Advance(time.Second)
+	_, ok := c.Get("ddd")
+	require.False(t, ok)
+
+	require.True(t, c.Delete("ccc"))
+	require.False(t, c.Delete("ccc"))
+
+	c.Set("eee", 5)
+	c.Set("fff", 6)
+	c.Clear()
+
+	require.Equal(t, []evictRecord{
+		{key: "aaa", value: 1, reason: EvictReplaced},
+		{key: "bbb", value: 2, reason: EvictCapacity},
+		{key: "aaa", value: 10, reason: EvictCapacity},
+		{key: "ddd", value: 4, reason: EvictExpired},
+		{key: "ccc", value: 3, reason: EvictDeleted},
+		{key: "eee", value: 5, reason: EvictCleared},
+		{key: "fff", value: 6, reason: EvictCleared},
+	}, records)
+}
+
+func TestOnEvictMayUseCache(t *testing.T) {
+	var c TypedCache[string, int]
+	c = NewTypedCacheWithConfig(Config[string, int]{
+		Capacity: 1,
+		OnEvict: func(key string, value int, reason EvictReason) {
+			if reason == EvictCapacity {
+				c.Stats()
+				c.Get(key)
+			}
+		},
+	})
+	c.Set("aaa", 1)
+	c.Set("bbb", 2)
+}
+
+func TestStats(t *testing.T) {
+	clock := newFakeClock()
+	c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 2, Clock: clock})
+
+	c.Set("aaa", 1)
+	c.Get("aaa")
+	c.Get("bbb")
+	c.Set("bbb", 2)
+	c.SetWithTTL("ccc", 3, time.Second) // вытесняет aaa
+	clock.Advance(time.Second)
+	c.Get("ccc") // просрочен
+	c.Delete("bbb")
+
+	require.Equal(t, Stats{Hits: 1, Misses: 2, Evictions: 2, Size: 0}, c.Stats())
+
+	c.Set("ddd", 4)
+	require.Equal(t, 1, c.Stats().Size)
+}
+
+func TestStatsMultithreading(t *testing.T) {
+	var evictions sync.Map
+	c := NewTypedCacheWithConfig(Config[string, int]{
+		Capacity: 10,
+		OnEvict: func(key string, _ int, _ EvictReason) {
+			evictions.Store(key, struct{}{})
+		},
+	})
+	wg := &sync.WaitGroup{}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := strconv.Itoa(g*1000 + i)
+				c.Set(key, i)
+				c.Get(key)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	stats := c.Stats()
+	require.Equal(t, 10, stats.Size)
+	require.Equal(t, uint64(4000-10), stats.Evictions)
+	require.Equal(t, stats.Hits+stats.Misses, uint64(4000))
+}
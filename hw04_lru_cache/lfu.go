@@ -0,0 +1,94 @@
+package hw04lrucache
+
+import "sort"
+
+// lfuPolicy keeps a list of items for every use count, so all operations take constant time
+// except looking for the rarest items after Delete has removed the last of them.
+type lfuPolicy[K comparable, V any] struct {
+	capacity int
+	size     int
+	minUses  int
+	buckets  map[int]*list[cacheItem[K, V]]
+}
+
+func (p *lfuPolicy[K, V]) add(ci cacheItem[K, V]) (item, victim *entry[K, V]) {
+	if p.size >= p.capacity {
+		victim = p.victim()
+		p.remove(victim)
+	}
+	ci.uses = 1
+	item = p.bucket(1).PushFront(ci)
+	p.minUses = 1
+	p.size++
+	return item, victim
+}
+
+func (p *lfuPolicy[K, V]) touch(item *entry[K, V]) {
+	uses := item.Value.uses
+	if p.unlink(item) && p.minUses == uses {
+		p.minUses = uses + 1
+	}
+	item.Value.uses++
+	p.bucket(uses + 1).pushFrontItem(item)
+}
+
+func (p *lfuPolicy[K, V]) remove(item *entry[K, V]) {
+	p.unlink(item)
+	p.size--
+}
+
+// unlink removes the item from its bucket and reports whether the bucket became empty.
+func (p *lfuPolicy[K, V]) unlink(item *entry[K, V]) bool {
+	bucket := p.buckets[item.Value.uses]
+	bucket.Remove(item)
+	if bucket.Len() > 0 {
+		return false
+	}
+	delete(p.buckets, item.Value.uses)
+	return true
+}
+
+func (p *lfuPolicy[K, V]) bucket(uses int) *list[cacheItem[K, V]] {
+	bucket, ok := p.buckets[uses]
+	if !ok {
+		bucket = new(list[cacheItem[K, V]])
+		p.buckets[uses] = bucket
+	}
+	return bucket
+}
+
+func (p *lfuPolicy[K, V]) victim() *entry[K, V] {
+	if p.size == 0 {
+		return nil
+	}
+	bucket, ok := p.buckets[p.minUses]
+	if !ok {
+		p.minUses = 0
+		for uses := range p.buckets {
+			if p.minUses == 0 || uses < p.minUses {
+				p.minUses = uses
+			}
+		}
+		bucket = p.buckets[p.minUses]
+	}
+	return bucket.Back()
+}
+
+func (p *lfuPolicy[K, V]) setCapacity(capacity int) {
+	p.capacity = capacity
+}
+
+func (p *lfuPolicy[K, V]) len() int {
+	return p.size
+}
+
+func (p *lfuPolicy[K, V]) each(fn func(item *entry[K, V])) {
+	uses := make([]int, 0, len(p.buckets))
+	for u := range p.buckets {
+		uses = append(uses, u)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(uses)))
+	for _, u := range uses {
+		eachItem(p.buckets[u], fn)
+	}
+}
@@ -1,91 +1,211 @@
 package hw04lrucache
 
-type List interface {
+import "iter"
+
+// TypedList is a doubly linked list of values of type T.
+// Methods taking an item do nothing if it belongs to another list.
+type TypedList[T any] interface {
 	Len() int
-	Front() *ListItem
-	Back() *ListItem
-	PushFront(v interface{}) *ListItem
-	PushBack(v interface{}) *ListItem
-	Remove(i *ListItem)
-	MoveToFront(i *ListItem)
+	Front() *TypedListItem[T]
+	Back() *TypedListItem[T]
+	PushFront(v T) *TypedListItem[T]
+	PushBack(v T) *TypedListItem[T]
+	// InsertBefore inserts v before mark, nil is returned if mark is not in the list.
+	InsertBefore(v T, mark *TypedListItem[T]) *TypedListItem[T]
+	// InsertAfter inserts v after mark, nil is returned if mark is not in the list.
+	InsertAfter(v T, mark *TypedListItem[T]) *TypedListItem[T]
+	// PushFrontList inserts copies of other values at the front, other may be the list itself.
+	PushFrontList(other TypedList[T])
+	// PushBackList inserts copies of other values at the back, other may be the list itself.
+	PushBackList(other TypedList[T])
+	Remove(i *TypedListItem[T])
+	MoveToFront(i *TypedListItem[T])
+	MoveToBack(i *TypedListItem[T])
+	MoveBefore(i, mark *TypedListItem[T])
+	MoveAfter(i, mark *TypedListItem[T])
+	// All iterates over values from the front. Items may be removed and inserted meanwhile,
+	// but if both the current item and the next one are removed, iteration stops.
+	All() iter.Seq[T]
+	// Backward iterates over values from the back, modifications are handled as by All.
+	Backward() iter.Seq[T]
 }
 
-func (lst *list) Len() int {
-	return lst.len
+type TypedListItem[T any] struct {
+	Value T
+	Next  *TypedListItem[T]
+	Prev  *TypedListItem[T]
+	// list is the list holding the item, nil after Remove.
+	list *list[T]
 }
 
-type ListItem struct {
-	Value interface{}
-	Next  *ListItem
-	Prev  *ListItem
+// List and ListItem keep the untyped API of the list.
+type (
+	List     = TypedList[interface{}]
+	ListItem = TypedListItem[interface{}]
+)
+
+func (lst *list[T]) Len() int {
+	return lst.len
 }
 
-type list struct {
+type list[T any] struct {
 	len   int
-	front *ListItem
-	back  *ListItem
+	front *TypedListItem[T]
+	back  *TypedListItem[T]
 }
 
-func (lst *list) Front() *ListItem {
+func (lst *list[T]) Front() *TypedListItem[T] {
 	return lst.front
 }
 
-func (lst *list) Back() *ListItem {
+func (lst *list[T]) Back() *TypedListItem[T] {
 	return lst.back
 }
 
-func (lst *list) PushFront(v interface{}) *ListItem {
-	item := &ListItem{Value: v, Next: lst.front}
+func (lst *list[T]) PushFront(v T) *TypedListItem[T] {
+	item := &TypedListItem[T]{Value: v}
+	lst.link(item, nil, lst.front)
+	return item
+}
 
-	if lst.front == nil {
-		lst.back = item
-	} else {
-		lst.front.Prev = item
-	}
-	lst.front = item
-	lst.len++
+func (lst *list[T]) PushBack(v T) *TypedListItem[T] {
+	item := &TypedListItem[T]{Value: v}
+	lst.link(item, lst.back, nil)
 	return item
 }
 
-func (lst *list) PushBack(v interface{}) *ListItem {
-	item := &ListItem{Value: v, Prev: lst.back}
+func (lst *list[T]) InsertBefore(v T, mark *TypedListItem[T]) *TypedListItem[T] {
+	if mark.list != lst {
+		return nil
+	}
+	item := &TypedListItem[T]{Value: v}
+	lst.link(item, mark.Prev, mark)
+	return item
+}
 
-	if lst.back == nil {
-		lst.back = item
-	} else {
-		lst.back.Next = item
+func (lst *list[T]) InsertAfter(v T, mark *TypedListItem[T]) *TypedListItem[T] {
+	if mark.list != lst {
+		return nil
 	}
-	lst.back = item
-	lst.len++
+	item := &TypedListItem[T]{Value: v}
+	lst.link(item, mark, mark.Next)
 	return item
 }
 
-func (lst *list) Remove(i *ListItem) {
-	lst.gap(i)
-	lst.len--
+func (lst *list[T]) PushFrontList(other TypedList[T]) {
+	// Длина запоминается заранее, чтобы вставка списка в самого себя закончилась.
+	for i, item := other.Len(), other.Back(); i > 0; i, item = i-1, item.Prev {
+		lst.PushFront(item.Value)
+	}
+}
+
+func (lst *list[T]) PushBackList(other TypedList[T]) {
+	for i, item := other.Len(), other.Front(); i > 0; i, item = i-1, item.Next {
+		lst.PushBack(item.Value)
+	}
 }
 
-func (lst *list) MoveToFront(i *ListItem) {
-	if lst.front == i {
+func (lst *list[T]) Remove(i *TypedListItem[T]) {
+	if i.list != lst {
 		return
 	}
-	if lst.back == i {
-		lst.back = i.Prev
-		lst.back.Next = nil
-	} else {
-		lst.gap(i)
+	lst.unlink(i)
+}
+
+func (lst *list[T]) MoveToFront(i *TypedListItem[T]) {
+	if i.list != lst || lst.front == i {
+		return
 	}
-	currFront := lst.front
+	lst.unlink(i)
+	lst.link(i, nil, lst.front)
+}
 
-	lst.front = i
-	lst.front.Prev = nil
-	lst.front.Next = currFront
-	lst.front.Next.Prev = i
+func (lst *list[T]) MoveToBack(i *TypedListItem[T]) {
+	if i.list != lst || lst.back == i {
+		return
+	}
+	lst.unlink(i)
+	lst.link(i, lst.back, nil)
 }
 
-// Функция для разрыва и сшивания новых связей
+func (lst *list[T]) MoveBefore(i, mark *TypedListItem[T]) {
+	if i.list != lst || mark.list != lst || i == mark {
+		return
+	}
+	lst.unlink(i)
+	lst.link(i, mark.Prev, mark)
+}
+
+func (lst *list[T]) MoveAfter(i, mark *TypedListItem[T]) {
+	if i.list != lst || mark.list != lst || i == mark {
+		return
+	}
+	lst.unlink(i)
+	lst.link(i, mark, mark.Next)
+}
+
+func (lst *list[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := lst.front; item != nil; {
+			next := item.Next
+			if !yield(item.Value) {
+				return
+			}
+			item = lst.step(item, item.Next, next)
+		}
+	}
+}
+
+func (lst *list[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := lst.back; item != nil; {
+			prev := item.Prev
+			if !yield(item.Value) {
+				return
+			}
+			item = lst.step(item, item.Prev, prev)
+		}
+	}
+}
+
+// step returns the item to iterate after current: its neighbour now if current is still
+// in the list, otherwise the neighbour saved before current was removed, nil if it is gone too.
+func (lst *list[T]) step(current, neighbour, saved *TypedListItem[T]) *TypedListItem[T] {
+	if current.list == lst {
+		return neighbour
+	}
+	if saved != nil && saved.list == lst {
+		return saved
+	}
+	return nil
+}
 
-func (lst *list) gap(item *ListItem) {
+// pushFrontItem links an item removed from another list, so its address stays the same.
+func (lst *list[T]) pushFrontItem(item *TypedListItem[T]) {
+	lst.link(item, nil, lst.front)
+}
+
+// Функции для сшивания и разрыва связей
+
+// link inserts the detached item between prev and next, nil stands for the end of the list.
+func (lst *list[T]) link(item, prev, next *TypedListItem[T]) {
+	item.Prev = prev
+	item.Next = next
+	if prev == nil {
+		lst.front = item
+	} else {
+		prev.Next = item
+	}
+	if next == nil {
+		lst.back = item
+	} else {
+		next.Prev = item
+	}
+	item.list = lst
+	lst.len++
+}
+
+func (lst *list[T]) unlink(item *TypedListItem[T]) {
 	if item.Next != nil {
 		item.Next.Prev = item.Prev
 	} else {
@@ -97,8 +217,16 @@ func (lst *list) gap(item *ListItem) {
 	} else {
 		lst.front = item.Next
 	}
+	item.Next = nil
+	item.Prev = nil
+	item.list = nil
+	lst.len--
+}
+
+func NewTypedList[T any]() TypedList[T] {
+	return new(list[T])
 }
 
 func NewList() List {
-	return new(list)
+	return NewTypedList[interface{}]()
 }
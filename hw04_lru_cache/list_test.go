@@ -1,6 +1,7 @@
 package hw04lrucache
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -63,4 +64,130 @@ func TestList(t *testing.T) {
 		}
 		require.Equal(t, []int{70, 80, 60, 40, 10, 30, 50}, elems)
 	})
+
+	t.Run("insert and move", func(t *testing.T) {
+		l := NewTypedList[int]()
+
+		b := l.PushBack(2)        // [2]
+		l.InsertBefore(1, b)      // [1, 2]
+		d := l.InsertAfter(4, b)  // [1, 2, 4]
+		l.InsertAfter(3, b)       // [1, 2, 3, 4]
+		l.MoveToBack(l.Front())   // [2, 3, 4, 1]
+		l.MoveBefore(d, b)        // [4, 2, 3, 1]
+		l.MoveAfter(l.Front(), b) // [2, 4, 3, 1]
+		l.MoveAfter(b, b)         // [2, 4, 3, 1]
+		l.MoveToBack(l.Back())    // [2, 4, 3, 1]
+		l.MoveBefore(l.Back(), b) // [1, 2, 4, 3]
+		require.Equal(t, []int{1, 2, 4, 3}, slices.Collect(l.All()))
+		require.Equal(t, []int{3, 4, 2, 1}, slices.Collect(l.Backward()))
+		require.Equal(t, 4, l.Len())
+		require.Equal(t, 1, l.Front().Value)
+		require.Equal(t, 3, l.Back().Value)
+	})
+
+	t.Run("foreign items", func(t *testing.T) {
+		l := NewTypedList[int]()
+		other := NewTypedList[int]()
+		l.PushBack(1)
+		foreign := other.PushBack(2)
+
+		l.Remove(foreign)
+		l.MoveToFront(foreign)
+		l.MoveToBack(foreign)
+		l.MoveBefore(foreign, l.Front())
+		l.MoveAfter(l.Front(), foreign)
+		require.Nil(t, l.InsertBefore(3, foreign))
+		require.Nil(t, l.InsertAfter(3, foreign))
+		require.Equal(t, []int{1}, slices.Collect(l.All()))
+		require.Equal(t, []int{2}, slices.Collect(other.All()))
+
+		item := l.Front()
+		l.Remove(item)
+		l.Remove(item) // повторное удаление ничего не портит
+		require.Equal(t, 0, l.Len())
+		require.Nil(t, l.Front())
+		require.Nil(t, l.Back())
+	})
+
+	t.Run("lists", func(t *testing.T) {
+		l := NewTypedList[int]()
+		other := NewTypedList[int]()
+		l.PushBack(3)
+		other.PushBack(1)
+		other.PushBack(2)
+
+		l.PushFrontList(other) // [1, 2, 3]
+		l.PushBackList(l)      // [1, 2, 3, 1, 2, 3]
+		l.PushFrontList(l)     // [1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3]
+		require.Equal(t, []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1, 2, 3}, slices.Collect(l.All()))
+		require.Equal(t, 12, l.Len())
+		require.Equal(t, 2, other.Len())
+	})
+
+	t.Run("iterators", func(t *testing.T) {
+		l := NewTypedList[int]()
+		items := make(map[int]*TypedListItem[int])
+		for i := 1; i <= 5; i++ {
+			items[i] = l.PushBack(i)
+		}
+
+		var seen []int
+		for v := range l.All() {
+			seen = append(seen, v)
+			if v%2 == 0 {
+				l.Remove(items[v])
+			}
+			if v == 3 {
+				break
+			}
+		}
+		require.Equal(t, []int{1, 2, 3}, seen)
+		require.Equal(t, []int{5, 4, 3, 1}, slices.Collect(l.Backward()))
+
+		// удаление следующего элемента не обрывает обход
+		seen = nil
+		for v := range l.All() {
+			seen = append(seen, v)
+			if v == 1 {
+				l.Remove(items[3])
+			}
+		}
+		require.Equal(t, []int{1, 4, 5}, seen)
+
+		seen = nil
+		items[2] = l.PushFront(2) // [2, 1, 4, 5]
+		for v := range l.Backward() {
+			seen = append(seen, v)
+			if v == 5 {
+				l.Remove(items[1])
+				l.InsertAfter(3, items[2])
+			}
+		}
+		require.Equal(t, []int{5, 4, 3, 2}, seen)
+
+		// удаление текущего и следующего элементов останавливает обход
+		seen = nil
+		for v := range l.All() {
+			seen = append(seen, v)
+			l.Remove(l.Front())
+			l.Remove(l.Front())
+		}
+		require.Equal(t, []int{2}, seen)
+		require.Equal(t, []int{4, 5}, slices.Collect(l.All()))
+	})
+}
+
+func TestTypedList(t *testing.T) {
+	l := NewTypedList[string]()
+
+	l.PushBack("b")  // [b]
+	l.PushFront("a") // [a, b]
+	last := l.PushBack("c")
+	l.MoveToFront(last) // [c, a, b]
+
+	elems := make([]string, 0, l.Len())
+	for i := l.Front(); i != nil; i = i.Next {
+		elems = append(elems, i.Value)
+	}
+	require.Equal(t, []string{"c", "a", "b"}, elems)
 }
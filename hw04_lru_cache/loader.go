@@ -0,0 +1,138 @@
+package hw04lrucache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLoaderPanicked is returned by GetOrLoad if the loader panicked.
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+// Loader returns the value of key for GetOrLoad.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// loadCall is a loader call shared by GetOrLoad calls for the same key.
+type loadCall[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	waiters int
+	// cancelled is set when all callers are gone, a new caller starts another call then.
+	cancelled bool
+	cancel    context.CancelFunc
+}
+
+// loadFailure is a loader error remembered for Config.ErrorTTL.
+type loadFailure struct {
+	err       error
+	expiresAt time.Time
+}
+
+func (cache *lruCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	cache.mutex.Lock()
+	if value, ok := cache.get(key); ok {
+		cache.unlock()
+		return value, nil
+	}
+	if err := cache.failure(key); err != nil {
+		cache.unlock()
+		var zero V
+		return zero, err
+	}
+	call, ok := cache.calls[key]
+	if !ok || call.cancelled {
+		call = cache.load(ctx, key, loader)
+	}
+	call.waiters++
+	cache.unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		cache.mutex.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancelled = true
+			call.cancel()
+		}
+		cache.mutex.Unlock()
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// failure returns the remembered loader error of key.
+func (cache *lruCache[K, V]) failure(key K) error {
+	failure, ok := cache.failures[key]
+	if !ok {
+		return nil
+	}
+	if !cache.clock.Now().Before(failure.expiresAt) {
+		delete(cache.failures, key)
+		return nil
+	}
+	return failure.err
+}
+
+// load starts the loader in a goroutine. Its context keeps the values of ctx
+// but is cancelled only when all waiting callers are gone.
+// Set, Delete and Clear detach the call, so its result only goes to the waiting callers.
+func (cache *lruCache[K, V]) load(ctx context.Context, key K, loader Loader[K, V]) *loadCall[V] {
+	loadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &loadCall[V]{done: make(chan struct{}), cancel: cancel}
+	cache.calls[key] = call
+
+	go func() {
+		defer cancel()
+		value, err := callLoader(loadCtx, key, loader)
+
+		cache.mutex.Lock()
+		current := cache.calls[key] == call
+		switch {
+		case !current:
+		case err == nil:
+			cache.set(key, value, cache.expiresAt(cache.defaultTTL), cache.weigh(key, value))
+		case cache.errorTTL > 0 && loadCtx.Err() == nil:
+			delete(cache.calls, key)
+			cache.rememberFailure(key, err)
+		default:
+			delete(cache.calls, key)
+		}
+		call.value, call.err = value, err
+		cache.unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// rememberFailure keeps err for Config.ErrorTTL unless the capacity is filled by live errors.
+func (cache *lruCache[K, V]) rememberFailure(key K, err error) {
+	if len(cache.failures) >= max(cache.capacity, 1) {
+		cache.removeExpiredFailures()
+		if len(cache.failures) >= max(cache.capacity, 1) {
+			return
+		}
+	}
+	cache.failures[key] = loadFailure{err: err, expiresAt: cache.clock.Now().Add(cache.errorTTL)}
+}
+
+func (cache *lruCache[K, V]) removeExpiredFailures() {
+	now := cache.clock.Now()
+	for key, failure := range cache.failures {
+		if !now.Before(failure.expiresAt) {
+			delete(cache.failures, key)
+		}
+	}
+}
+
+func callLoader[K comparable, V any](ctx context.Context, key K, loader Loader[K, V]) (value V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrLoaderPanicked, r)
+		}
+	}()
+	return loader(ctx, key)
+}
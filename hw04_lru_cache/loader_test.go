@@ -0,0 +1,243 @@
+package hw04lrucache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errBackend = errors.New("backend is down")
+
+func TestGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("coalescing", func(t *testing.T) {
+		c := NewTypedCache[string, int](5)
+		var calls int32
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key string) (int, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return len(key), nil
+		}
+
+		const callers = 10
+		results := make(chan int, callers)
+		errs := make(chan error, callers)
+		wg := &sync.WaitGroup{}
+		wg.Add(callers)
+		for i := 0; i < callers; i++ {
+			go func() {
+				defer wg.Done()
+				val, err := c.GetOrLoad(ctx, "aaa", loader)
+				results <- val
+				errs <- err
+			}()
+		}
+		require.Eventually(t, func() bool {
+			return c.Stats().Misses == callers
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(results)
+		close(errs)
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		for val := range results {
+			require.Equal(t, 3, val)
+			require.NoError(t, <-errs)
+		}
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 3, val)
+
+		val, err := c.GetOrLoad(ctx, "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, 3, val)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		c := NewTypedCache[string, int](5)
+		calls := 0
+		loader := func(ctx context.Context, key string) (int, error) {
+			calls++
+			return 0, errBackend
+		}
+
+		for i := 0; i < 3; i++ {
+			_, err := c.GetOrLoad(ctx, "aaa", loader)
+			require.Truef(t, errors.Is(err, errBackend), "actual error %q", err)
+		}
+		require.Equal(t, 3, calls)
+		require.False(t, c.Contains("aaa"))
+	})
+
+	t.Run("error ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 5, Clock: clock, ErrorTTL: time.Second})
+		calls := 0
+		loader := func(ctx context.Context, key string) (int, error) {
+			calls++
+			if calls%2 == 1 {
+				return 0, errBackend
+			}
+			return calls, nil
+		}
+
+		for i := 0; i < 3; i++ {
+			_, err := c.GetOrLoad(ctx, "aaa", loader)
+			require.Truef(t, errors.Is(err, errBackend), "actual error %q", err)
+		}
+		require.Equal(t, 1, calls)
+
+		clock.Advance(time.Second)
+		val, err := c.GetOrLoad(ctx, "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, 2, val)
+
+		_, err = c.GetOrLoad(ctx, "bbb", loader)
+		require.Truef(t, errors.Is(err, errBackend), "actual error %q", err)
+		c.Delete("bbb") // Delete забывает и ошибку
+		val, err = c.GetOrLoad(ctx, "bbb", loader)
+		require.NoError(t, err)
+		require.Equal(t, 4, val)
+	})
+
+	t.Run("error limit", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 2, Clock: clock, ErrorTTL: time.Second})
+		calls := 0
+		loader := func(ctx context.Context, key int) (int, error) {
+			calls++
+			return 0, errBackend
+		}
+
+		for key := 0; key < 3; key++ {
+			c.GetOrLoad(ctx, key, loader)
+		}
+		c.GetOrLoad(ctx, 1, loader)
+		require.Equal(t, 3, calls, "errors within the capacity are remembered")
+		c.GetOrLoad(ctx, 2, loader)
+		require.Equal(t, 4, calls, "errors beyond the capacity are not")
+		require.Len(t, c.(*lruCache[int, int]).failures, 2)
+
+		clock.Advance(time.Second) // истёкшие ошибки освобождают место
+		c.GetOrLoad(ctx, 2, loader)
+		c.GetOrLoad(ctx, 2, loader)
+		require.Equal(t, 5, calls)
+		require.Len(t, c.(*lruCache[int, int]).failures, 1)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		c := NewTypedCache[string, int](5)
+		loaderDone := make(chan error, 1)
+		loader := func(ctx context.Context, key string) (int, error) {
+			<-ctx.Done()
+			loaderDone <- ctx.Err()
+			return 0, ctx.Err()
+		}
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := c.GetOrLoad(cancelCtx, "aaa", loader)
+		require.Truef(t, errors.Is(err, context.Canceled), "actual error %q", err)
+		require.Truef(t, errors.Is(<-loaderDone, context.Canceled), "loader context must be cancelled")
+	})
+
+	t.Run("cancel one of callers", func(t *testing.T) {
+		c := NewTypedCache[string, int](5)
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key string) (int, error) {
+			select {
+			case <-release:
+				return 1, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+
+		result := make(chan error, 1)
+		go func() {
+			_, err := c.GetOrLoad(ctx, "aaa", loader)
+			result <- err
+		}()
+		require.Eventually(t, func() bool {
+			return c.Stats().Misses == 1
+		}, time.Second, time.Millisecond)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := c.GetOrLoad(cancelCtx, "aaa", loader)
+		require.Truef(t, errors.Is(err, context.Canceled), "actual error %q", err)
+
+		close(release)
+		require.NoError(t, <-result)
+		require.True(t, c.Contains("aaa"))
+	})
+
+	t.Run("write during load", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			write    func(c TypedCache[string, int])
+			expected bool
+		}{
+			{name: "delete", write: func(c TypedCache[string, int]) { c.Delete("aaa") }},
+			{name: "clear", write: func(c TypedCache[string, int]) { c.Clear() }},
+			{name: "set", write: func(c TypedCache[string, int]) { c.Set("aaa", 2) }, expected: true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				c := NewTypedCache[string, int](5)
+				release := make(chan struct{})
+				loader := func(ctx context.Context, key string) (int, error) {
+					<-release
+					return 1, nil
+				}
+
+				result := make(chan int, 1)
+				go func() {
+					val, _ := c.GetOrLoad(ctx, "aaa", loader)
+					result <- val
+				}()
+				require.Eventually(t, func() bool {
+					return c.Stats().Misses == 1
+				}, time.Second, time.Millisecond)
+
+				tc.write(c)
+				close(release)
+				require.Equal(t, 1, <-result, "waiting callers get the loaded value")
+				val, ok := c.Peek("aaa")
+				require.Equal(t, tc.expected, ok, "the loaded value must not be stored")
+				if ok {
+					require.Equal(t, 2, val)
+				}
+			})
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		c := NewTypedCache[string, int](5)
+		_, err := c.GetOrLoad(ctx, "aaa", func(ctx context.Context, key string) (int, error) {
+			panic("oops")
+		})
+		require.Truef(t, errors.Is(err, ErrLoaderPanicked), "actual error %q", err)
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		c := newShardedCache(Config[int, string]{Capacity: 16}, 4, identityHash)
+		for i := 0; i < 32; i++ {
+			val, err := c.GetOrLoad(ctx, i%8, func(ctx context.Context, key int) (string, error) {
+				return strconv.Itoa(key), nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, strconv.Itoa(i%8), val)
+		}
+		require.Equal(t, Stats{Hits: 24, Misses: 8, Size: 8}, c.Stats())
+	})
+}
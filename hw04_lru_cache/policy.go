@@ -0,0 +1,192 @@
+package hw04lrucache
+
+import "fmt"
+
+// Policy selects the entry evicted when the cache is full.
+type Policy int
+
+const (
+	// PolicyLRU evicts the least recently used entry.
+	PolicyLRU Policy = iota
+	// PolicyLFU evicts the least frequently used entry, the least recently used one among equals.
+	PolicyLFU
+	// Policy2Q puts new entries into a FIFO queue and moves an entry into the main LRU queue
+	// only if it is requested again soon after leaving the FIFO, so a scan can't flush the main queue.
+	Policy2Q
+	// PolicyARC splits the cache between recently and frequently used entries and adapts
+	// the split to the workload using keys of recently evicted entries.
+	PolicyARC
+	// PolicyTinyLFU keeps new entries in a small LRU window and moves them into the main
+	// segmented LRU only if they are estimated to be used more often than the entry to be evicted.
+	PolicyTinyLFU
+)
+
+func (p Policy) String() string {
+	switch p {
+	case PolicyLRU:
+		return "LRU"
+	case PolicyLFU:
+		return "LFU"
+	case Policy2Q:
+		return "2Q"
+	case PolicyARC:
+		return "ARC"
+	case PolicyTinyLFU:
+		return "W-TinyLFU"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+type entry[K comparable, V any] = TypedListItem[cacheItem[K, V]]
+
+// evictionPolicy keeps the list items holding cache entries and decides which one is evicted.
+type evictionPolicy[K comparable, V any] interface {
+	// add stores a new entry and returns its item along with the item evicted to make room,
+	// which is already removed from the policy, or nil.
+	add(ci cacheItem[K, V]) (item, victim *entry[K, V])
+	// touch records a use of the item.
+	touch(item *entry[K, V])
+	remove(item *entry[K, V])
+	// victim returns the item to be evicted next, nil if there are no items.
+	victim() *entry[K, V]
+	// setCapacity changes the capacity, the cache evicts victims until the items fit.
+	setCapacity(capacity int)
+	len() int
+	// each calls fn for the items from the one to be evicted last to the next victim.
+	each(fn func(item *entry[K, V]))
+}
+
+func newPolicy[K comparable, V any](policy Policy, capacity int) evictionPolicy[K, V] {
+	var p evictionPolicy[K, V]
+	switch policy {
+	case PolicyLRU:
+		p = new(lruPolicy[K, V])
+	case PolicyLFU:
+		p = &lfuPolicy[K, V]{buckets: make(map[int]*list[cacheItem[K, V]])}
+	case Policy2Q:
+		p = &twoQueuePolicy[K, V]{ghosts: newGhostList[K]()}
+	case PolicyARC:
+		p = &arcPolicy[K, V]{recentGhosts: newGhostList[K](), frequentGhosts: newGhostList[K]()}
+	case PolicyTinyLFU:
+		p = new(tinyLFUPolicy[K, V])
+	default:
+		panic(fmt.Sprintf("hw04lrucache: unknown policy %v", policy))
+	}
+	p.setCapacity(capacity)
+	return p
+}
+
+type lruPolicy[K comparable, V any] struct {
+	capacity int
+	queue    list[cacheItem[K, V]]
+}
+
+func (p *lruPolicy[K, V]) add(ci cacheItem[K, V]) (item, victim *entry[K, V]) {
+	if p.queue.Len() >= p.capacity {
+		victim = p.queue.Back()
+		p.queue.Remove(victim)
+	}
+	return p.queue.PushFront(ci), victim
+}
+
+func (p *lruPolicy[K, V]) touch(item *entry[K, V]) {
+	p.queue.MoveToFront(item)
+}
+
+func (p *lruPolicy[K, V]) remove(item *entry[K, V]) {
+	p.queue.Remove(item)
+}
+
+func (p *lruPolicy[K, V]) victim() *entry[K, V] {
+	return p.queue.Back()
+}
+
+func (p *lruPolicy[K, V]) setCapacity(capacity int) {
+	p.capacity = capacity
+}
+
+func (p *lruPolicy[K, V]) len() int {
+	return p.queue.Len()
+}
+
+func (p *lruPolicy[K, V]) each(fn func(item *entry[K, V])) {
+	eachItem(&p.queue, fn)
+}
+
+func eachItem[T any](lst *list[T], fn func(item *TypedListItem[T])) {
+	for item := lst.Front(); item != nil; item = item.Next {
+		fn(item)
+	}
+}
+
+// eachItemSplit calls fn for the first keep items of lst, then for the items of others and
+// at last for the rest of lst. Policies evicting the excess of a segment over keep first,
+// and the segment itself last, list their items in victim order this way.
+func eachItemSplit[T any](lst *list[T], keep int, fn func(item *TypedListItem[T]), others ...*list[T]) {
+	item := lst.Front()
+	for ; item != nil && keep > 0; item, keep = item.Next, keep-1 {
+		fn(item)
+	}
+	for _, other := range others {
+		eachItem(other, fn)
+	}
+	for ; item != nil; item = item.Next {
+		fn(item)
+	}
+}
+
+// ghostList remembers keys of evicted entries, the oldest keys are forgotten first.
+type ghostList[K comparable] struct {
+	capacity int
+	order    list[K]
+	keys     map[K]*TypedListItem[K]
+}
+
+func newGhostList[K comparable]() *ghostList[K] {
+	return &ghostList[K]{keys: make(map[K]*TypedListItem[K])}
+}
+
+func (g *ghostList[K]) push(key K) {
+	if g.capacity <= 0 {
+		return
+	}
+	g.remove(key)
+	g.keys[key] = g.order.PushFront(key)
+	g.trim()
+}
+
+func (g *ghostList[K]) contains(key K) bool {
+	_, ok := g.keys[key]
+	return ok
+}
+
+// remove forgets the key and reports whether it was remembered.
+func (g *ghostList[K]) remove(key K) bool {
+	item, ok := g.keys[key]
+	if ok {
+		g.order.Remove(item)
+		delete(g.keys, key)
+	}
+	return ok
+}
+
+func (g *ghostList[K]) removeOldest() {
+	if oldest := g.order.Back(); oldest != nil {
+		g.remove(oldest.Value)
+	}
+}
+
+func (g *ghostList[K]) setCapacity(capacity int) {
+	g.capacity = capacity
+	g.trim()
+}
+
+func (g *ghostList[K]) trim() {
+	for g.order.Len() > g.capacity {
+		g.removeOldest()
+	}
+}
+
+func (g *ghostList[K]) len() int {
+	return g.order.Len()
+}
@@ -0,0 +1,330 @@
+package hw04lrucache
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var policies = []Policy{PolicyLRU, PolicyLFU, Policy2Q, PolicyARC, PolicyTinyLFU}
+
+func TestPolicies(t *testing.T) {
+	for _, policy := range policies {
+		t.Run(policy.String(), func(t *testing.T) {
+			t.Run("simple", func(t *testing.T) {
+				c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 5, Policy: policy})
+
+				require.False(t, c.Set("aaa", 100))
+				require.False(t, c.Set("bbb", 200))
+				require.True(t, c.Set("aaa", 300))
+
+				val, ok := c.Get("aaa")
+				require.True(t, ok)
+				require.Equal(t, 300, val)
+				_, ok = c.Get("ccc")
+				require.False(t, ok)
+
+				require.True(t, c.Delete("bbb"))
+				require.Equal(t, []string{"aaa"}, c.Keys())
+				require.Equal(t, Stats{Hits: 1, Misses: 1, Size: 1}, c.Stats())
+			})
+
+			t.Run("capacity", func(t *testing.T) {
+				evicted := 0
+				c := NewTypedCacheWithConfig(Config[int, int]{
+					Capacity: 50,
+					Policy:   policy,
+					OnEvict: func(key, value int, reason EvictReason) {
+						evicted++
+					},
+				})
+				rnd := rand.New(rand.NewSource(1))
+				for i := 0; i < 10_000; i++ {
+					key := rnd.Intn(200)
+					if _, ok := c.Get(key); !ok {
+						c.Set(key, key)
+					}
+					if i%100 == 0 {
+						c.Delete(rnd.Intn(200))
+					}
+					require.LessOrEqual(t, c.Len(), 50)
+				}
+				require.Len(t, c.Keys(), c.Len())
+				for _, key := range c.Keys() {
+					val, ok := c.Peek(key)
+					require.True(t, ok)
+					require.Equal(t, key, val)
+				}
+
+				require.Equal(t, c.Len()-10, c.Resize(10))
+				require.Equal(t, 10, c.Len())
+				require.Len(t, c.Keys(), 10)
+				c.Resize(50)
+
+				c.Clear()
+				require.Equal(t, 0, c.Len())
+				c.Set(1, 1)
+				require.Equal(t, []int{1}, c.Keys())
+
+				stats := c.Stats()
+				require.Equal(t, 10_000, int(stats.Hits+stats.Misses))
+				require.Less(t, 0, evicted)
+			})
+
+			t.Run("keys order", func(t *testing.T) {
+				c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 20, Policy: policy})
+				rnd := rand.New(rand.NewSource(1))
+				for i := 0; i < 1000; i++ {
+					key := rnd.Intn(40)
+					if _, ok := c.Get(key); !ok {
+						c.Set(key, key)
+					}
+				}
+				keys := c.Keys()
+
+				// Снимаем жертвы политики по одной, их порядок обратен Keys.
+				p := c.(*lruCache[int, int]).policy
+				victims := make([]int, 0, len(keys))
+				for victim := p.victim(); victim != nil; victim = p.victim() {
+					p.remove(victim)
+					victims = append(victims, victim.Value.key)
+				}
+				slices.Reverse(victims)
+				require.Equal(t, keys, victims, "keys must be listed in victim order")
+			})
+
+			t.Run("multithreading", func(t *testing.T) {
+				c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 10, Policy: policy})
+				wg := &sync.WaitGroup{}
+				wg.Add(2)
+
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 100_000; i++ {
+						c.Set(i%1000, i)
+					}
+				}()
+
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 100_000; i++ {
+						c.Get(rand.Intn(1000))
+					}
+				}()
+
+				wg.Wait()
+				require.LessOrEqual(t, c.Len(), 10)
+			})
+		})
+	}
+}
+
+func TestPolicyLFU(t *testing.T) {
+	c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 3, Policy: PolicyLFU})
+	c.Set("aaa", 1)
+	c.Set("bbb", 2)
+	c.Set("ccc", 3)
+	c.Get("aaa")
+	c.Get("aaa")
+	c.Get("bbb")
+
+	c.Set("ddd", 4) // вытесняет ccc, к которому не обращались
+	require.False(t, c.Contains("ccc"))
+	c.Set("eee", 5) // вытесняет ddd: из равных выбывает давний
+	require.Equal(t, []string{"aaa", "bbb", "eee"}, c.Keys())
+
+	c.Delete("eee")
+	c.Set("fff", 6)
+	require.Equal(t, []string{"aaa", "bbb", "fff"}, c.Keys())
+}
+
+func TestPolicy2Q(t *testing.T) {
+	c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 8, Policy: Policy2Q})
+	for i := 0; i < 8; i++ {
+		c.Set(i, i)
+	}
+	c.Set(8, 8) // 0 вытесняется из in, но ключ запоминается
+	require.False(t, c.Contains(0))
+	c.Set(0, 0) // и поэтому возвращается сразу в main
+	// Переживёт всех, кроме двух новых записей, которые in держит в пределах inCapacity.
+	require.Equal(t, []int{8, 7, 0}, c.Keys()[:3])
+
+	for i := 100; i < 200; i++ {
+		c.Set(i, i)
+	}
+	require.True(t, c.Contains(0), "scan must not flush main")
+}
+
+func TestPolicyARC(t *testing.T) {
+	c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 4, Policy: PolicyARC})
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(1)
+	c.Get(2) // 1 и 2 используются часто
+	for i := 100; i < 200; i++ {
+		c.Set(i, i)
+	}
+	require.True(t, c.Contains(1))
+	require.True(t, c.Contains(2))
+}
+
+// mixHash is splitmix64: a predictable hash spreading int keys like a random one.
+func mixHash(key int) uint64 {
+	x := uint64(key) + 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// fixSketch replaces the random hash of the W-TinyLFU sketch, so collisions are the same every run.
+func fixSketch[V any](c TypedCache[int, V]) {
+	c.(*lruCache[int, V]).policy.(*tinyLFUPolicy[int, V]).sketch.hash = mixHash
+}
+
+func TestPolicyTinyLFU(t *testing.T) {
+	c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 100, Policy: PolicyTinyLFU})
+	fixSketch(c)
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+		for j := 0; j < 3; j++ {
+			c.Get(i)
+		}
+	}
+	for i := 1000; i < 2000; i++ {
+		c.Set(i, i)
+	}
+	hot := 0
+	for i := 0; i < 100; i++ {
+		if c.Contains(i) {
+			hot++
+		}
+	}
+	// Хэш предсказуем, поэтому граница точная: теряются лишь ключи, вытесненные из окна.
+	require.GreaterOrEqual(t, hot, 97, "keys used once must not push out hot ones")
+
+	t.Run("sketch", func(t *testing.T) {
+		s := newFrequencySketch[int](100)
+		s.hash = mixHash
+		for i := 0; i < 20; i++ {
+			s.increment(1)
+		}
+		s.increment(2)
+		require.Equal(t, uint8(sketchMaxCount), s.estimate(1))
+		require.GreaterOrEqual(t, s.estimate(2), uint8(1))
+
+		for i := 0; i < 1000; i++ {
+			s.increment(3)
+		}
+		require.Less(t, s.estimate(1), uint8(sketchMaxCount), "counters must be halved")
+	})
+
+	t.Run("sketch resize", func(t *testing.T) {
+		s := newFrequencySketch[int](100)
+		s.hash = mixHash
+		for i := 0; i < 5; i++ {
+			s.increment(1)
+		}
+
+		s.setCapacity(1000)
+		require.Len(t, s.counters[0], 4096)
+		require.Equal(t, 10_000, s.resetAt)
+		require.Equal(t, uint8(5), s.estimate(1), "counters must be kept")
+
+		s.setCapacity(10)
+		require.Len(t, s.counters[0], 64)
+		require.Equal(t, uint8(5), s.estimate(1), "counters must be kept")
+
+		for i := 0; i < 50; i++ {
+			s.increment(2)
+		}
+		s.setCapacity(2) // 55 прибавлений больше нового resetAt 20
+		require.Equal(t, uint8(7), s.estimate(2), "counters must be halved")
+	})
+}
+
+// trace is a sequence of requested keys.
+type trace struct {
+	name string
+	keys []int
+}
+
+// zipfTrace requests keys with Zipf distribution, like most real workloads.
+func zipfTrace(rnd *rand.Rand, n, keys int) []int {
+	zipf := rand.NewZipf(rnd, 1.1, 1, uint64(keys-1))
+	trace := make([]int, n)
+	for i := range trace {
+		trace[i] = int(zipf.Uint64())
+	}
+	return trace
+}
+
+// scanTrace is a zipfTrace interrupted by sequential scans over never repeated keys.
+func scanTrace(rnd *rand.Rand, n, keys, scan int) []int {
+	trace := zipfTrace(rnd, n, keys)
+	next := keys
+	for start := 0; start+2*scan <= len(trace); start += 4 * scan {
+		for i := start; i < start+scan; i++ {
+			trace[i] = next
+			next++
+		}
+	}
+	return trace
+}
+
+// loopTrace requests keys in a loop slightly longer than the cache.
+func loopTrace(n, keys int) []int {
+	trace := make([]int, n)
+	for i := range trace {
+		trace[i] = i % keys
+	}
+	return trace
+}
+
+func traces(capacity int) []trace {
+	rnd := rand.New(rand.NewSource(1))
+	return []trace{
+		{name: "zipf", keys: zipfTrace(rnd, 200_000, 50*capacity)},
+		{name: "scan", keys: scanTrace(rnd, 200_000, 50*capacity, 2*capacity)},
+		{name: "loop", keys: loopTrace(200_000, capacity+capacity/4)},
+	}
+}
+
+// hitRatio replays the trace setting every missing key and returns the share of hits.
+func hitRatio(policy Policy, capacity int, keys []int) float64 {
+	c := NewTypedCacheWithConfig(Config[int, int]{Capacity: capacity, Policy: policy})
+	for _, key := range keys {
+		if _, ok := c.Get(key); !ok {
+			c.Set(key, key)
+		}
+	}
+	stats := c.Stats()
+	return float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+}
+
+func TestPolicyScanResistance(t *testing.T) {
+	const capacity = 500
+	keys := scanTrace(rand.New(rand.NewSource(1)), 200_000, 50*capacity, 2*capacity)
+	lru := hitRatio(PolicyLRU, capacity, keys)
+	for _, policy := range []Policy{Policy2Q, PolicyARC, PolicyTinyLFU} {
+		require.Greaterf(t, hitRatio(policy, capacity, keys), lru, "%v", policy)
+	}
+}
+
+func BenchmarkPolicyHitRatio(b *testing.B) {
+	const capacity = 1000
+	for _, tr := range traces(capacity) {
+		for _, policy := range policies {
+			b.Run(tr.name+"/"+policy.String(), func(b *testing.B) {
+				var ratio float64
+				for i := 0; i < b.N; i++ {
+					ratio = hitRatio(policy, capacity, tr.keys)
+				}
+				b.ReportMetric(100*ratio, "hit%")
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(tr.keys)), "ns/access")
+			})
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package hw04lrucache
+
+import (
+	"context"
+	"hash/maphash"
+	"io"
+	"time"
+)
+
+// shardedCache spreads keys over independent caches by hash, so goroutines
+// working with different keys rarely wait for the same mutex.
+// Recency is tracked per shard: with PolicyLRU an entry is evicted when it is the least recently
+// used one of its shard, and Keys lists shards one after another.
+type shardedCache[K comparable, V any] struct {
+	hash   func(key K) uint64
+	shards []*lruCache[K, V]
+}
+
+// NewShardedCache creates a cache of shards parts sharing cfg.Capacity and cfg.MaxWeight,
+// so an entry heavier than the share of its shard is not stored. There are no more shards
+// than the capacity and the weight limit allow to give every shard at least one entry.
+//
+// Every shard evicts on its own when its share is full, so keys spread unevenly make
+// the cache evict while holding fewer entries than the capacity.
+// Sharding pays off only when many CPUs use the cache at once: on a few CPUs hashing
+// costs more than waiting for a single mutex, so NewTypedCacheWithConfig is faster there.
+func NewShardedCache[K comparable, V any](cfg Config[K, V], shards int) TypedCache[K, V] {
+	seed := maphash.MakeSeed()
+	return newShardedCache(cfg, shards, func(key K) uint64 {
+		return maphash.Comparable(seed, key)
+	})
+}
+
+// newShardedCache spreads keys over shards by hash, tests pass a predictable one.
+func newShardedCache[K comparable, V any](cfg Config[K, V], shards int, hash func(key K) uint64) *shardedCache[K, V] {
+	if cfg.Capacity > 0 {
+		shards = min(shards, cfg.Capacity)
+	}
+	if cfg.MaxWeight > 0 {
+		shards = int(min(int64(shards), cfg.MaxWeight))
+	}
+	shards = max(shards, 1)
+	cache := &shardedCache[K, V]{
+		hash:   hash,
+		shards: make([]*lruCache[K, V], shards),
+	}
+	capacities := splitCapacity(cfg.Capacity, shards)
+	weights := splitCapacity(cfg.MaxWeight, shards)
+	for i := range cache.shards {
+		shardCfg := cfg
+		shardCfg.Capacity = capacities[i]
+		shardCfg.MaxWeight = weights[i]
+		cache.shards[i] = NewTypedCacheWithConfig(shardCfg).(*lruCache[K, V])
+	}
+	return cache
+}
+
+// splitCapacity divides capacity into parts differing by one at most. Positive capacity
+// less than parts gives 1 to every part: zero would disable caching or the weight limit.
+func splitCapacity[T int | int64](capacity T, parts int) []T {
+	capacities := make([]T, parts)
+	for i := range capacities {
+		capacities[i] = capacity / T(parts)
+		if T(i) < capacity%T(parts) {
+			capacities[i]++
+		}
+		if capacity > 0 {
+			capacities[i] = max(capacities[i], 1)
+		}
+	}
+	return capacities
+}
+
+func (cache *shardedCache[K, V]) shard(key K) *lruCache[K, V] {
+	return cache.shards[cache.hash(key)%uint64(len(cache.shards))]
+}
+
+func (cache *shardedCache[K, V]) Set(key K, value V) bool {
+	return cache.shard(key).Set(key, value)
+}
+
+func (cache *shardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) bool {
+	return cache.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (cache *shardedCache[K, V]) SetWithWeight(key K, value V, weight int64) bool {
+	return cache.shard(key).SetWithWeight(key, value, weight)
+}
+
+func (cache *shardedCache[K, V]) Get(key K) (V, bool) {
+	return cache.shard(key).Get(key)
+}
+
+func (cache *shardedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[K, V]) (V, error) {
+	return cache.shard(key).GetOrLoad(ctx, key, loader)
+}
+
+func (cache *shardedCache[K, V]) Peek(key K) (V, bool) {
+	return cache.shard(key).Peek(key)
+}
+
+func (cache *shardedCache[K, V]) Contains(key K) bool {
+	return cache.shard(key).Contains(key)
+}
+
+func (cache *shardedCache[K, V]) Delete(key K) bool {
+	return cache.shard(key).Delete(key)
+}
+
+func (cache *shardedCache[K, V]) Keys() []K {
+	var keys []K
+	for _, shard := range cache.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+func (cache *shardedCache[K, V]) Len() int {
+	length := 0
+	for _, shard := range cache.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+// Resize splits capacity over the shards, capacity less than the number of shards
+// still leaves one entry to every shard.
+func (cache *shardedCache[K, V]) Resize(capacity int) int {
+	evicted := 0
+	for i, shardCapacity := range splitCapacity(capacity, len(cache.shards)) {
+		evicted += cache.shards[i].Resize(shardCapacity)
+	}
+	return evicted
+}
+
+func (cache *shardedCache[K, V]) Clear() {
+	for _, shard := range cache.shards {
+		shard.Clear()
+	}
+}
+
+func (cache *shardedCache[K, V]) Stats() Stats {
+	var stats Stats
+	for _, shard := range cache.shards {
+		shardStats := shard.Stats()
+		stats.Hits += shardStats.Hits
+		stats.Misses += shardStats.Misses
+		stats.Evictions += shardStats.Evictions
+		stats.Rejections += shardStats.Rejections
+		stats.Size += shardStats.Size
+		stats.Weight += shardStats.Weight
+	}
+	return stats
+}
+
+// Snapshot writes shards one after another: the order of entries is kept within
+// a shard, but there is no order across shards.
+func (cache *shardedCache[K, V]) Snapshot(w io.Writer) error {
+	var entries []snapshotEntry[K, V]
+	for _, shard := range cache.shards {
+		entries = append(entries, shard.entries()...)
+	}
+	first := cache.shards[0]
+	return writeSnapshot(w, first.codec, first.maxWeight > 0, entries)
+}
+
+// Restore spreads entries over shards by the hash of this cache, which differs from
+// the one of the cache that wrote the snapshot. A shard getting more entries than its share
+// evicts those written earlier, even if they were used later than entries of other shards.
+func (cache *shardedCache[K, V]) Restore(r io.Reader) error {
+	header, entries, err := readSnapshot[K, V](r, cache.shards[0].codec)
+	if err != nil {
+		return err
+	}
+	shardEntries := make(map[*lruCache[K, V]][]snapshotEntry[K, V], len(cache.shards))
+	for _, e := range entries {
+		shard := cache.shard(e.Key)
+		shardEntries[shard] = append(shardEntries[shard], e)
+	}
+	for shard, entries := range shardEntries {
+		shard.restore(entries, header.Weighted)
+	}
+	return nil
+}
+
+func (cache *shardedCache[K, V]) Close() {
+	for _, shard := range cache.shards {
+		shard.Close()
+	}
+}
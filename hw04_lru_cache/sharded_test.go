@@ -0,0 +1,170 @@
+package hw04lrucache
+
+import (
+	"math/rand"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedCache(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		c := NewShardedCache(Config[Key, interface{}]{Capacity: 16}, 4)
+
+		require.False(t, c.Set("aaa", 100))
+		require.True(t, c.Set("aaa", 200))
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+
+		val, ok = c.Peek("aaa")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+		require.True(t, c.Contains("aaa"))
+		require.Equal(t, []Key{"aaa"}, c.Keys())
+
+		require.True(t, c.Delete("aaa"))
+		_, ok = c.Get("aaa")
+		require.False(t, ok)
+		require.Equal(t, Stats{Hits: 1, Misses: 1}, c.Stats())
+	})
+
+	t.Run("capacity is shared", func(t *testing.T) {
+		c := NewShardedCache(Config[int, int]{Capacity: 10}, 3)
+		for i := 0; i < 1000; i++ {
+			c.Set(i, i)
+		}
+		require.LessOrEqual(t, c.Len(), 10)
+		require.Len(t, c.Keys(), c.Len())
+
+		c.Resize(4)
+		require.LessOrEqual(t, c.Len(), 4)
+
+		c.Clear()
+		require.Equal(t, 0, c.Len())
+		c.Close()
+	})
+
+	t.Run("split capacity", func(t *testing.T) {
+		require.Equal(t, []int{4, 3, 3}, splitCapacity(10, 3))
+		require.Equal(t, []int{1, 1, 1, 1}, splitCapacity(2, 4))
+		require.Equal(t, []int64{0, 0}, splitCapacity[int64](0, 2))
+	})
+
+	t.Run("small capacity", func(t *testing.T) {
+		c := newShardedCache(Config[int, int]{Capacity: 2}, 4, identityHash)
+		require.Len(t, c.shards, 2, "every shard must hold an entry")
+		for i := 0; i < 10; i++ {
+			c.Set(i, i)
+		}
+		require.Equal(t, []int{8, 9}, c.Keys())
+
+		c = newShardedCache(Config[int, int]{Capacity: 10, MaxWeight: 3}, 4, identityHash)
+		require.Len(t, c.shards, 3)
+		require.False(t, c.SetWithWeight(1, 1, 2), "the share of the shard is exceeded")
+		require.False(t, c.Contains(1))
+		c.SetWithWeight(1, 1, 1)
+		require.True(t, c.Contains(1))
+
+		c.Resize(2)
+		for _, shard := range c.shards {
+			require.Equal(t, 1, shard.capacity)
+		}
+	})
+
+	t.Run("uneven keys", func(t *testing.T) {
+		// Все ключи попадают в один шард, и он вытесняет, хотя кэш заполнен на четверть.
+		c := newShardedCache(Config[int, int]{Capacity: 8}, 4, func(key int) uint64 { return 0 })
+		for i := 0; i < 8; i++ {
+			c.Set(i, i)
+		}
+		require.Equal(t, 2, c.Len())
+	})
+
+	t.Run("multithreading", func(t *testing.T) {
+		c := NewShardedCache(Config[Key, interface{}]{Capacity: 10}, 8)
+		wg := &sync.WaitGroup{}
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100_000; i++ {
+				c.Set(Key(strconv.Itoa(i)), i)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100_000; i++ {
+				c.Get(Key(strconv.Itoa(rand.Intn(100_000))))
+			}
+		}()
+
+		wg.Wait()
+		require.LessOrEqual(t, c.Len(), 10)
+	})
+}
+
+// identityHash spreads int keys over shards predictably: key modulo the number of shards.
+func identityHash(key int) uint64 {
+	return uint64(key)
+}
+
+// benchmarkReadHeavy does nine Get per Set from all goroutines at once.
+func benchmarkReadHeavy(b *testing.B, c TypedCache[int, int]) {
+	b.Helper()
+	const keys = 10_000
+	for i := 0; i < keys; i++ {
+		c.Set(i, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		rnd := rand.New(rand.NewSource(rand.Int63()))
+		for i := 0; pb.Next(); i++ {
+			key := rnd.Intn(keys)
+			if i%10 == 0 {
+				c.Set(key, i)
+			} else {
+				c.Get(key)
+			}
+		}
+	})
+}
+
+// BenchmarkParallel compares caches on every number of CPUs up to NumCPU, the sharded cache
+// wins only when several CPUs contend for the mutex.
+func BenchmarkParallel(b *testing.B) {
+	for procs := 1; procs <= runtime.NumCPU(); procs *= 2 {
+		b.Run("procs "+strconv.Itoa(procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+
+			b.Run("single mutex", func(b *testing.B) {
+				benchmarkReadHeavy(b, NewTypedCache[int, int](5_000))
+			})
+
+			b.Run("sharded 16", func(b *testing.B) {
+				benchmarkReadHeavy(b, NewShardedCache(Config[int, int]{Capacity: 5_000}, 16))
+			})
+		})
+	}
+}
+
+// BenchmarkContended runs four goroutines per CPU, the sharded cache needs at least 2 CPUs to win.
+func BenchmarkContended(b *testing.B) {
+	if runtime.NumCPU() < 2 {
+		b.Skip("contention needs at least 2 CPUs")
+	}
+	b.Run("single mutex", func(b *testing.B) {
+		b.SetParallelism(4)
+		benchmarkReadHeavy(b, NewTypedCache[int, int](5_000))
+	})
+
+	b.Run("sharded 16", func(b *testing.B) {
+		b.SetParallelism(4)
+		benchmarkReadHeavy(b, NewShardedCache(Config[int, int]{Capacity: 5_000}, 16))
+	})
+}
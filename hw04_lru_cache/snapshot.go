@@ -0,0 +1,162 @@
+package hw04lrucache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// ErrSnapshotVersion is returned by Restore for snapshots written by an incompatible version.
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+const snapshotVersion = 1
+
+// Encoder writes values to a stream, *gob.Encoder and *json.Encoder implement it.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder reads values written by the Encoder of the same codec.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// Codec serializes keys and values of snapshots.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+func (gobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+var (
+	// GobCodec writes snapshots with encoding/gob. Concrete types stored
+	// in interface values must be registered with gob.Register.
+	GobCodec Codec = gobCodec{}
+	// JSONCodec writes snapshots as JSON lines. Values are restored the way
+	// encoding/json decodes them, so numbers in interface values become float64.
+	JSONCodec Codec = jsonCodec{}
+)
+
+type snapshotHeader struct {
+	Version int
+	Len     int
+	// Weighted tells that entries keep their weights, otherwise Restore weighs them again.
+	Weighted bool
+}
+
+type snapshotEntry[K comparable, V any] struct {
+	Key       K
+	Value     V
+	ExpiresAt time.Time `json:",omitzero"`
+	Weight    int64     `json:",omitzero"`
+}
+
+// Snapshot writes live entries from the next to be evicted to the one evicted last,
+// so Restore adding them in this order repeats the LRU order.
+// Other policies only keep the order, not the use counts.
+func (cache *lruCache[K, V]) Snapshot(w io.Writer) error {
+	return writeSnapshot(w, cache.codec, cache.maxWeight > 0, cache.entries())
+}
+
+// Restore adds entries of the snapshot as if they were set one after another,
+// expired entries are skipped. Nothing is added if the snapshot can't be read.
+func (cache *lruCache[K, V]) Restore(r io.Reader) error {
+	header, entries, err := readSnapshot[K, V](r, cache.codec)
+	if err != nil {
+		return err
+	}
+	cache.restore(entries, header.Weighted)
+	return nil
+}
+
+func (cache *lruCache[K, V]) entries() []snapshotEntry[K, V] {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entries := make([]snapshotEntry[K, V], 0, cache.policy.len())
+	cache.policy.each(func(item *TypedListItem[cacheItem[K, V]]) {
+		if !cache.expired(item) {
+			entries = append(entries, snapshotEntry[K, V]{
+				Key:       item.Value.key,
+				Value:     item.Value.value,
+				ExpiresAt: item.Value.expiresAt,
+				Weight:    item.Value.weight,
+			})
+		}
+	})
+	slices.Reverse(entries)
+	return entries
+}
+
+func (cache *lruCache[K, V]) restore(entries []snapshotEntry[K, V], weighted bool) {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	now := cache.clock.Now()
+	for _, e := range entries {
+		if !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt) {
+			continue
+		}
+		weight := e.Weight
+		if !weighted {
+			weight = cache.weigh(e.Key, e.Value)
+		}
+		cache.set(e.Key, e.Value, e.ExpiresAt, weight)
+	}
+}
+
+func writeSnapshot[K comparable, V any](w io.Writer, codec Codec, weighted bool, entries []snapshotEntry[K, V]) error {
+	enc := codec.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Version: snapshotVersion, Len: len(entries), Weighted: weighted}); err != nil {
+		return fmt.Errorf("snapshot header: %w", err)
+	}
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("snapshot entry %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func readSnapshot[K comparable, V any](r io.Reader, codec Codec) (snapshotHeader, []snapshotEntry[K, V], error) {
+	dec := codec.NewDecoder(r)
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return header, nil, fmt.Errorf("snapshot header: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return header, nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, header.Version)
+	}
+	entries := make([]snapshotEntry[K, V], 0, min(max(header.Len, 0), 1<<16))
+	for i := 0; i < header.Len; i++ {
+		var e snapshotEntry[K, V]
+		if err := dec.Decode(&e); err != nil {
+			return header, nil, fmt.Errorf("snapshot entry %d: %w", i, err)
+		}
+		entries = append(entries, e)
+	}
+	return header, entries, nil
+}
@@ -0,0 +1,141 @@
+package hw04lrucache
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type snapshotValue struct {
+	Name  string
+	Count int
+}
+
+func TestSnapshot(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		codec Codec
+	}{
+		{name: "gob", codec: GobCodec},
+		{name: "json", codec: JSONCodec},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := newFakeClock()
+			cfg := Config[string, snapshotValue]{Capacity: 5, Clock: clock, Codec: tc.codec}
+			c := NewTypedCacheWithConfig(cfg)
+			c.Set("aaa", snapshotValue{Name: "a", Count: 1})
+			c.Set("bbb", snapshotValue{Name: "b", Count: 2})
+			c.SetWithTTL("ccc", snapshotValue{Name: "c", Count: 3}, time.Minute)
+			c.SetWithTTL("ddd", snapshotValue{Name: "d", Count: 4}, time.Second)
+			c.Get("aaa")
+			clock.Advance(time.Second) // ddd истекает и не попадает в снимок
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, c.Snapshot(buf))
+
+			restored := NewTypedCacheWithConfig(cfg)
+			require.NoError(t, restored.Restore(bytes.NewReader(buf.Bytes())))
+			require.Equal(t, []string{"aaa", "ccc", "bbb"}, restored.Keys())
+			val, ok := restored.Peek("ccc")
+			require.True(t, ok)
+			require.Equal(t, snapshotValue{Name: "c", Count: 3}, val)
+
+			clock.Advance(time.Minute)
+			require.False(t, restored.Contains("ccc"), "ttl must be restored")
+
+			expired := NewTypedCacheWithConfig(cfg)
+			require.NoError(t, expired.Restore(bytes.NewReader(buf.Bytes())))
+			require.Equal(t, []string{"aaa", "bbb"}, expired.Keys())
+		})
+	}
+
+	t.Run("capacity", func(t *testing.T) {
+		c := NewTypedCache[int, int](10)
+		for i := 0; i < 10; i++ {
+			c.Set(i, i)
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, c.Snapshot(buf))
+
+		small := NewTypedCache[int, int](3)
+		require.NoError(t, small.Restore(buf))
+		require.Equal(t, []int{9, 8, 7}, small.Keys())
+	})
+
+	t.Run("weights", func(t *testing.T) {
+		c := NewTypedCacheWithConfig(Config[string, string]{Capacity: 10, MaxWeight: 10})
+		c.SetWithWeight("aaa", "a", 6)
+		c.SetWithWeight("bbb", "b", 3)
+		buf := &bytes.Buffer{}
+		require.NoError(t, c.Snapshot(buf))
+
+		restored := NewTypedCacheWithConfig(Config[string, string]{Capacity: 10, MaxWeight: 10})
+		require.NoError(t, restored.Restore(buf))
+		require.Equal(t, int64(9), restored.Stats().Weight)
+	})
+
+	t.Run("untyped", func(t *testing.T) {
+		c := NewCache(5)
+		c.Set("aaa", 1)
+		c.Set("bbb", "text")
+		buf := &bytes.Buffer{}
+		require.NoError(t, c.Snapshot(buf))
+
+		restored := NewCache(5)
+		require.NoError(t, restored.Restore(buf))
+		val, ok := restored.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 1, val)
+		val, ok = restored.Get("bbb")
+		require.True(t, ok)
+		require.Equal(t, "text", val)
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		cfg := Config[int, int]{Capacity: 100, Codec: JSONCodec}
+		c := newShardedCache(cfg, 4, identityHash)
+		for i := 0; i < 50; i++ {
+			c.Set(i, i*i)
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, c.Snapshot(buf))
+
+		restored := newShardedCache(cfg, 4, identityHash)
+		require.NoError(t, restored.Restore(buf))
+		require.Equal(t, 50, restored.Len())
+		val, ok := restored.Get(7)
+		require.True(t, ok)
+		require.Equal(t, 49, val)
+	})
+
+	t.Run("sharded by another hash", func(t *testing.T) {
+		cfg := Config[int, int]{Capacity: 8, Codec: JSONCodec}
+		c := newShardedCache(cfg, 4, identityHash)
+		for i := 0; i < 8; i++ {
+			c.Set(i, i)
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, c.Snapshot(buf)) // 0 4 1 5 2 6 3 7: шард за шардом
+
+		// Все ключи попадают в один шард на две записи: остаются записанные последними,
+		// а не использованные последними 6 и 7.
+		restored := newShardedCache(cfg, 4, func(key int) uint64 { return 0 })
+		require.NoError(t, restored.Restore(buf))
+		require.Equal(t, []int{7, 3}, restored.Keys())
+	})
+
+	t.Run("broken", func(t *testing.T) {
+		c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 5, Codec: JSONCodec})
+
+		err := c.Restore(strings.NewReader(`{"Version":2,"Len":0}`))
+		require.Truef(t, errors.Is(err, ErrSnapshotVersion), "actual error %q", err)
+
+		err = c.Restore(strings.NewReader(`{"Version":1,"Len":2}` + "\n" + `{"Key":1,"Value":1}`))
+		require.Error(t, err)
+		require.Equal(t, 0, c.Len(), "nothing must be restored from a broken snapshot")
+	})
+}
@@ -0,0 +1,222 @@
+package hw04lrucache
+
+import "hash/maphash"
+
+const (
+	segmentWindow uint8 = iota
+	segmentProbation
+	segmentProtected
+)
+
+// tinyLFUPolicy is W-TinyLFU from Caffeine. New entries go into the LRU window taking
+// 1% of the capacity. An entry leaving the window is admitted into the main segmented LRU
+// only if the sketch estimates it was used more often than the probation victim. An entry
+// used again in probation moves to protected taking 80% of the main part.
+type tinyLFUPolicy[K comparable, V any] struct {
+	capacity          int
+	windowCapacity    int
+	protectedCapacity int
+	window            list[cacheItem[K, V]]
+	probation         list[cacheItem[K, V]]
+	protected         list[cacheItem[K, V]]
+	sketch            *frequencySketch[K]
+}
+
+func (p *tinyLFUPolicy[K, V]) add(ci cacheItem[K, V]) (item, victim *entry[K, V]) {
+	p.sketch.increment(ci.key)
+	ci.segment = segmentWindow
+	item = p.window.PushFront(ci)
+	if p.window.Len() <= p.windowCapacity {
+		return item, nil
+	}
+
+	candidate := p.window.Back()
+	p.window.Remove(candidate)
+	if p.probation.Len()+p.protected.Len() < p.capacity-p.windowCapacity {
+		p.admit(candidate)
+		return item, nil
+	}
+	victim = p.probation.Back()
+	if victim == nil {
+		victim = p.protected.Back()
+	}
+	if victim == nil || p.sketch.estimate(candidate.Value.key) <= p.sketch.estimate(victim.Value.key) {
+		return item, candidate
+	}
+	p.remove(victim)
+	p.admit(candidate)
+	return item, victim
+}
+
+func (p *tinyLFUPolicy[K, V]) admit(item *entry[K, V]) {
+	item.Value.segment = segmentProbation
+	p.probation.pushFrontItem(item)
+}
+
+func (p *tinyLFUPolicy[K, V]) touch(item *entry[K, V]) {
+	p.sketch.increment(item.Value.key)
+	switch item.Value.segment {
+	case segmentWindow:
+		p.window.MoveToFront(item)
+	case segmentProtected:
+		p.protected.MoveToFront(item)
+	case segmentProbation:
+		p.probation.Remove(item)
+		item.Value.segment = segmentProtected
+		p.protected.pushFrontItem(item)
+		if p.protected.Len() > p.protectedCapacity {
+			demoted := p.protected.Back()
+			p.protected.Remove(demoted)
+			p.admit(demoted)
+		}
+	}
+}
+
+func (p *tinyLFUPolicy[K, V]) remove(item *entry[K, V]) {
+	switch item.Value.segment {
+	case segmentWindow:
+		p.window.Remove(item)
+	case segmentProbation:
+		p.probation.Remove(item)
+	case segmentProtected:
+		p.protected.Remove(item)
+	}
+}
+
+func (p *tinyLFUPolicy[K, V]) victim() *entry[K, V] {
+	if p.window.Len() > p.windowCapacity {
+		return p.window.Back()
+	}
+	for _, segment := range []*list[cacheItem[K, V]]{&p.probation, &p.protected, &p.window} {
+		if victim := segment.Back(); victim != nil {
+			return victim
+		}
+	}
+	return nil
+}
+
+// setCapacity also resizes the sketch keeping the counted uses.
+func (p *tinyLFUPolicy[K, V]) setCapacity(capacity int) {
+	p.capacity = capacity
+	p.windowCapacity = max(capacity/100, 1)
+	p.protectedCapacity = (capacity - p.windowCapacity) * 4 / 5
+	if p.sketch == nil {
+		p.sketch = newFrequencySketch[K](capacity)
+	} else {
+		p.sketch.setCapacity(capacity)
+	}
+}
+
+func (p *tinyLFUPolicy[K, V]) len() int {
+	return p.window.Len() + p.probation.Len() + p.protected.Len()
+}
+
+func (p *tinyLFUPolicy[K, V]) each(fn func(item *entry[K, V])) {
+	// Окно сверх windowCapacity вытесняется первым, затем probation, protected и остаток окна.
+	eachItemSplit(&p.window, p.windowCapacity, fn, &p.protected, &p.probation)
+}
+
+const (
+	sketchDepth    = 4
+	sketchMaxCount = 15
+)
+
+// frequencySketch is a count-min sketch of key uses with four counters per cached
+// entry in a row. Counters saturate at 15 and are halved after 10 uses per cached entry,
+// so old popularity fades away.
+type frequencySketch[K comparable] struct {
+	// hash is random for every sketch, tests replace it with a predictable one.
+	hash      func(key K) uint64
+	counters  [sketchDepth][]uint8
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+func newFrequencySketch[K comparable](capacity int) *frequencySketch[K] {
+	seed := maphash.MakeSeed()
+	width := sketchWidth(capacity)
+	s := &frequencySketch[K]{
+		hash: func(key K) uint64 {
+			return maphash.Comparable(seed, key)
+		},
+		mask:    uint64(width - 1),
+		resetAt: 10 * max(capacity, 1),
+	}
+	for i := range s.counters {
+		s.counters[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// sketchWidth returns the power of two not less than four counters per entry.
+func sketchWidth(capacity int) int {
+	width := 16
+	for width < 4*capacity {
+		width <<= 1
+	}
+	return width
+}
+
+// setCapacity resizes rows keeping counts: a wider row copies every counter to the cells
+// of the keys it counted, a narrower one keeps the greatest of the merged counters,
+// so estimates still don't go below real counts.
+func (s *frequencySketch[K]) setCapacity(capacity int) {
+	if width := sketchWidth(capacity); width != len(s.counters[0]) {
+		for row, old := range s.counters {
+			counters := make([]uint8, width)
+			if width > len(old) {
+				for i := range counters {
+					counters[i] = old[uint64(i)&s.mask]
+				}
+			} else {
+				for i, count := range old {
+					counters[i&(width-1)] = max(counters[i&(width-1)], count)
+				}
+			}
+			s.counters[row] = counters
+		}
+		s.mask = uint64(width - 1)
+	}
+	s.resetAt = 10 * max(capacity, 1)
+	if s.additions >= s.resetAt {
+		s.halve()
+	}
+}
+
+// index returns the counter of key in the row, rows use different hashes derived
+// from one by double hashing.
+func (s *frequencySketch[K]) index(hash uint64, row int) uint64 {
+	return (hash + uint64(row)*(hash>>32|1)) & s.mask
+}
+
+func (s *frequencySketch[K]) increment(key K) {
+	hash := s.hash(key)
+	for row := range s.counters {
+		if counter := &s.counters[row][s.index(hash, row)]; *counter < sketchMaxCount {
+			*counter++
+		}
+	}
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.halve()
+	}
+}
+
+func (s *frequencySketch[K]) estimate(key K) uint8 {
+	hash := s.hash(key)
+	estimate := uint8(sketchMaxCount)
+	for row := range s.counters {
+		estimate = min(estimate, s.counters[row][s.index(hash, row)])
+	}
+	return estimate
+}
+
+func (s *frequencySketch[K]) halve() {
+	for row := range s.counters {
+		for i := range s.counters[row] {
+			s.counters[row][i] >>= 1
+		}
+	}
+	s.additions /= 2
+}
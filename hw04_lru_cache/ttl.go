@@ -0,0 +1,72 @@
+package hw04lrucache
+
+import "time"
+
+// Clock tells the current time, tests substitute it to control expiration.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// expiresAt returns the expiration time for ttl, zero time means never.
+func (cache *lruCache[K, V]) expiresAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return cache.clock.Now().Add(ttl)
+}
+
+func (cache *lruCache[K, V]) expired(item *TypedListItem[cacheItem[K, V]]) bool {
+	expiresAt := item.Value.expiresAt
+	return !expiresAt.IsZero() && !cache.clock.Now().Before(expiresAt)
+}
+
+func (cache *lruCache[K, V]) removeExpired() {
+	cache.mutex.Lock()
+	defer cache.unlock()
+
+	var expired []*TypedListItem[cacheItem[K, V]]
+	cache.policy.each(func(item *TypedListItem[cacheItem[K, V]]) {
+		if cache.expired(item) {
+			expired = append(expired, item)
+		}
+	})
+	for _, item := range expired {
+		cache.removeItem(item, EvictExpired)
+	}
+	cache.removeExpiredFailures()
+}
+
+func (cache *lruCache[K, V]) startJanitor(interval time.Duration) {
+	cache.stop = make(chan struct{})
+	cache.done = make(chan struct{})
+	go func() {
+		defer close(cache.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				cache.removeExpired()
+			case <-cache.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the janitor goroutine and waits for it to exit.
+func (cache *lruCache[K, V]) Close() {
+	cache.closeOnce.Do(func() {
+		if cache.stop == nil {
+			return
+		}
+		close(cache.stop)
+		<-cache.done
+	})
+}
@@ -0,0 +1,131 @@
+package hw04lrucache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Run("per entry ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 5, Clock: clock})
+
+		c.SetWithTTL("short", 1, time.Second)
+		c.SetWithTTL("long", 2, time.Minute)
+		c.Set("forever", 3)
+
+		clock.Advance(999 * time.Millisecond)
+		_, ok := c.Get("short")
+		require.True(t, ok)
+
+		clock.Advance(time.Millisecond)
+		_, ok = c.Get("short")
+		require.False(t, ok)
+		val, ok := c.Get("long")
+		require.True(t, ok)
+		require.Equal(t, 2, val)
+
+		clock.Advance(24 * time.Hour)
+		_, ok = c.Get("long")
+		require.False(t, ok)
+		_, ok = c.Get("forever")
+		require.True(t, ok)
+	})
+
+	t.Run("default ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 5, Clock: clock, DefaultTTL: time.Second})
+
+		c.Set("aaa", 1)
+		c.SetWithTTL("bbb", 2, 0)
+		clock.Advance(time.Second)
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		_, ok = c.Get("bbb")
+		require.True(t, ok)
+	})
+
+	t.Run("set renews ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 5, Clock: clock, DefaultTTL: time.Second})
+
+		c.Set("aaa", 1)
+		clock.Advance(900 * time.Millisecond)
+		require.True(t, c.Set("aaa", 2))
+		clock.Advance(900 * time.Millisecond)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 2, val)
+	})
+
+	t.Run("expired entry frees space", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 2, Clock: clock})
+
+		c.SetWithTTL("aaa", 1, time.Second)
+		c.Set("bbb", 2)
+		clock.Advance(time.Second)
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+
+		require.False(t, c.Set("ccc", 3))
+		_, ok = c.Get("bbb")
+		require.True(t, ok)
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		clock := newFakeClock()
+		c := NewTypedCacheWithConfig(Config[string, int]{
+			Capacity:        5,
+			Clock:           clock,
+			JanitorInterval: time.Millisecond,
+		})
+		defer c.Close()
+		cache := c.(*lruCache[string, int])
+
+		c.SetWithTTL("aaa", 1, time.Second)
+		c.Set("bbb", 2)
+		clock.Advance(time.Second)
+
+		require.Eventually(t, func() bool {
+			cache.mutex.Lock()
+			defer cache.mutex.Unlock()
+			return cache.policy.len() == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		c := NewTypedCacheWithConfig(Config[string, int]{Capacity: 5, JanitorInterval: time.Millisecond})
+		c.Close()
+		c.Close()
+
+		// Без уборщика Close ничего не делает.
+		NewTypedCache[string, int](5).Close()
+	})
+}
@@ -0,0 +1,73 @@
+package hw04lrucache
+
+const (
+	segmentIn uint8 = iota
+	segmentMain
+)
+
+// twoQueuePolicy is the full version of 2Q by Johnson and Shasha: new entries wait in
+// the FIFO in, keys evicted from it are remembered in ghosts, and an entry coming back
+// while its key is remembered goes into the LRU main.
+type twoQueuePolicy[K comparable, V any] struct {
+	capacity   int
+	inCapacity int
+	in         list[cacheItem[K, V]]
+	main       list[cacheItem[K, V]]
+	ghosts     *ghostList[K]
+}
+
+func (p *twoQueuePolicy[K, V]) add(ci cacheItem[K, V]) (item, victim *entry[K, V]) {
+	seen := p.ghosts.remove(ci.key)
+	if p.len() >= p.capacity {
+		victim = p.victim()
+		p.remove(victim)
+		if victim.Value.segment == segmentIn {
+			p.ghosts.push(victim.Value.key)
+		}
+	}
+	if seen {
+		ci.segment = segmentMain
+		return p.main.PushFront(ci), victim
+	}
+	ci.segment = segmentIn
+	return p.in.PushFront(ci), victim
+}
+
+func (p *twoQueuePolicy[K, V]) touch(item *entry[K, V]) {
+	// Повторное обращение к записи из in ничего не меняет: это и защищает main от сканирования.
+	if item.Value.segment == segmentMain {
+		p.main.MoveToFront(item)
+	}
+}
+
+func (p *twoQueuePolicy[K, V]) remove(item *entry[K, V]) {
+	if item.Value.segment == segmentMain {
+		p.main.Remove(item)
+	} else {
+		p.in.Remove(item)
+	}
+}
+
+func (p *twoQueuePolicy[K, V]) victim() *entry[K, V] {
+	if p.in.Len() > p.inCapacity || p.main.Len() == 0 {
+		return p.in.Back()
+	}
+	return p.main.Back()
+}
+
+// setCapacity gives a quarter of the capacity to in and remembers keys for a half of it,
+// as the paper suggests.
+func (p *twoQueuePolicy[K, V]) setCapacity(capacity int) {
+	p.capacity = capacity
+	p.inCapacity = max(capacity/4, 1)
+	p.ghosts.setCapacity(max(capacity/2, 1))
+}
+
+func (p *twoQueuePolicy[K, V]) len() int {
+	return p.in.Len() + p.main.Len()
+}
+
+func (p *twoQueuePolicy[K, V]) each(fn func(item *entry[K, V])) {
+	// Записи in сверх inCapacity вытесняются первыми, остальные — когда main опустеет.
+	eachItemSplit(&p.in, p.inCapacity, fn, &p.main)
+}
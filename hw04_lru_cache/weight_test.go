@@ -0,0 +1,139 @@
+package hw04lrucache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheWeight(t *testing.T) {
+	t.Run("weigher", func(t *testing.T) {
+		var records []evictRecord
+		c := NewTypedCacheWithConfig(Config[string, string]{
+			Capacity:  100,
+			MaxWeight: 10,
+			Weigher: func(key string, value string) int64 {
+				return int64(len(value))
+			},
+			OnEvict: func(key string, value string, reason EvictReason) {
+				records = append(records, evictRecord{key: key, value: len(value), reason: reason})
+			},
+		})
+
+		c.Set("aaa", "1234")
+		c.Set("bbb", "123")
+		c.Set("ccc", "12")
+		require.Equal(t, Stats{Size: 3, Weight: 9}, c.Stats())
+
+		c.Set("ddd", "123456") // вытесняет aaa и bbb
+		require.Equal(t, []string{"ddd", "ccc"}, c.Keys())
+		require.Equal(t, int64(8), c.Stats().Weight)
+		require.Equal(t, []evictRecord{
+			{key: "aaa", value: 4, reason: EvictCapacity},
+			{key: "bbb", value: 3, reason: EvictCapacity},
+		}, records)
+	})
+
+	t.Run("too heavy", func(t *testing.T) {
+		var records []evictRecord
+		c := NewTypedCacheWithConfig(Config[string, int]{
+			Capacity:  100,
+			MaxWeight: 10,
+			OnEvict: func(key string, value int, reason EvictReason) {
+				records = append(records, evictRecord{key: key, value: value, reason: reason})
+			},
+		})
+
+		require.False(t, c.SetWithWeight("aaa", 1, 5))
+		require.False(t, c.SetWithWeight("bbb", 2, 11))
+		require.False(t, c.SetWithWeight("ccc", 3, -1))
+		require.False(t, c.Contains("bbb"))
+		require.False(t, c.Contains("ccc"))
+		require.Equal(t, Stats{Rejections: 2, Size: 1, Weight: 5}, c.Stats())
+
+		require.Empty(t, records)
+
+		// значение, которое не помещается, не должно оставлять в кэше старое
+		require.True(t, c.SetWithWeight("aaa", 4, 20))
+		require.False(t, c.Contains("aaa"))
+		require.Equal(t, Stats{Rejections: 3}, c.Stats())
+		require.Equal(t, []evictRecord{{key: "aaa", value: 1, reason: EvictReplaced}}, records)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		var records []evictRecord
+		c := NewTypedCacheWithConfig(Config[string, int]{
+			Capacity:  100,
+			MaxWeight: 10,
+			OnEvict: func(key string, value int, reason EvictReason) {
+				records = append(records, evictRecord{key: key, value: value, reason: reason})
+			},
+		})
+		c.SetWithWeight("aaa", 1, 3)
+		c.SetWithWeight("bbb", 2, 3)
+		c.SetWithWeight("ccc", 3, 3)
+
+		require.True(t, c.SetWithWeight("aaa", 10, 1))
+		require.Equal(t, int64(7), c.Stats().Weight)
+		require.Equal(t, []string{"aaa", "ccc", "bbb"}, c.Keys())
+
+		// более тяжёлое значение вытесняет другие записи, а не само себя
+		require.True(t, c.SetWithWeight("aaa", 20, 7))
+		require.Equal(t, []string{"aaa", "ccc"}, c.Keys())
+		require.Equal(t, int64(10), c.Stats().Weight)
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 20, val)
+		require.Equal(t, []evictRecord{
+			{key: "aaa", value: 1, reason: EvictReplaced},
+			{key: "aaa", value: 10, reason: EvictReplaced},
+			{key: "bbb", value: 2, reason: EvictCapacity},
+		}, records)
+	})
+
+	t.Run("capacity and weight", func(t *testing.T) {
+		c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 3, MaxWeight: 100})
+		for i := 0; i < 10; i++ {
+			c.Set(i, i)
+		}
+		require.Equal(t, Stats{Evictions: 7, Size: 3, Weight: 3}, c.Stats())
+
+		c.Delete(9)
+		c.SetWithTTL(10, 10, time.Minute)
+		require.Equal(t, int64(3), c.Stats().Weight)
+		c.Clear()
+		require.Equal(t, int64(0), c.Stats().Weight)
+	})
+
+	t.Run("without max weight", func(t *testing.T) {
+		c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 3})
+		c.SetWithWeight(1, 1, 1000)
+		c.SetWithWeight(2, 2, -1)
+		require.Equal(t, Stats{Size: 2}, c.Stats())
+	})
+
+	t.Run("policies", func(t *testing.T) {
+		for _, policy := range policies {
+			c := NewTypedCacheWithConfig(Config[int, int]{Capacity: 100, MaxWeight: 50, Policy: policy})
+			for i := 0; i < 1000; i++ {
+				c.SetWithWeight(i%200, i, int64(i%7))
+				c.Get(i % 13)
+				require.LessOrEqual(t, c.Stats().Weight, int64(50), policy)
+			}
+		}
+	})
+
+	t.Run("sharded", func(t *testing.T) {
+		c := NewShardedCache(Config[int, int]{Capacity: 100, MaxWeight: 40}, 4)
+		for i := 0; i < 1000; i++ {
+			c.SetWithWeight(i, i, 3)
+		}
+		stats := c.Stats()
+		require.LessOrEqual(t, stats.Weight, int64(40))
+		require.Equal(t, int64(3*stats.Size), stats.Weight)
+
+		require.False(t, c.SetWithWeight(-1, -1, 11))
+		require.Equal(t, uint64(1), c.Stats().Rejections)
+	})
+}